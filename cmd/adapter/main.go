@@ -5,9 +5,11 @@ import (
 	"errors"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -47,17 +49,98 @@ func (l *lazySTTServer) StreamTranscription(stream napv1.SpeechToTextService_Str
 	return (*srv).StreamTranscription(stream)
 }
 
+// lazyHTTPHandler is lazySTTServer's REST counterpart: it lets startRESTServer
+// bind and start serving cfg.RESTAddr before the engine has finished loading,
+// returning 503 with a Retry-After hint for any request that arrives first.
+type lazyHTTPHandler struct {
+	handler atomic.Pointer[http.Handler]
+}
+
+func (l *lazyHTTPHandler) setHandler(h http.Handler) {
+	l.handler.Store(&h)
+}
+
+func (l *lazyHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := l.handler.Load()
+	if h == nil {
+		w.Header().Set("Retry-After", "2")
+		http.Error(w, "model is still loading, please retry in a moment", http.StatusServiceUnavailable)
+		return
+	}
+	(*h).ServeHTTP(w, r)
+}
+
 func main() {
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
+	ctx, cancel := context.WithCancel(rootCtx)
+	defer cancel()
 
-	cfg, err := config.Loader{}.Load()
+	configs, err := config.Loader{}.LoadAll()
 	if err != nil {
 		slog.Error("failed to load configuration", "error", err)
 		os.Exit(1)
 	}
 
+	// A single shared model manager backs every alias: config.Loader.LoadAll
+	// normalises DataDir to one shared value across instances, so every
+	// alias in this process shares the same model cache.
+	manager, err := models.NewManager(configs[0].DataDir, newLogger(configs[0].LogLevel))
+	if err != nil {
+		slog.Error("failed to initialise model manager", "error", err)
+		os.Exit(1)
+	}
+
+	caps := engine.NativeAvailable()
+	slog.Info("native accelerator capabilities",
+		"native", caps.Native, "metal", caps.Metal, "cuda", caps.CUDA,
+		"core_ml", caps.CoreML, "open_vino", caps.OpenVINO, "blas", caps.BLAS, "vulkan", caps.Vulkan,
+	)
+
+	instances := make([]server.InstanceInfo, len(configs))
+	for i, cfg := range configs {
+		instances[i] = server.InstanceInfo{
+			Alias:        cfg.Alias,
+			ListenAddr:   cfg.ListenAddr,
+			RESTAddr:     cfg.RESTAddr,
+			ModelVariant: cfg.ModelVariant,
+			Language:     cfg.Language,
+		}
+	}
+
+	var (
+		wg          sync.WaitGroup
+		failed      atomic.Bool
+		engineSetup sync.Mutex
+	)
+	for _, cfg := range configs {
+		wg.Add(1)
+		go func(cfg config.Config) {
+			defer wg.Done()
+			if err := runInstance(ctx, cfg, manager, &engineSetup, instances); err != nil {
+				failed.Store(true)
+				cancel()
+			}
+		}(cfg)
+	}
+	wg.Wait()
+
+	if failed.Load() {
+		os.Exit(1)
+	}
+}
+
+// runInstance brings up one alias's gRPC listener, REST transcription
+// endpoint, and telemetry exporter, and blocks until ctx is cancelled or the
+// gRPC server fails. Every log line and metric it emits is tagged with
+// cfg.Alias (empty for a single-instance adapter). engineSetup is held for
+// the engine.New call only, so two aliases loading the same model variant
+// through the shared manager never race on its on-disk download.
+func runInstance(ctx context.Context, cfg config.Config, manager *models.Manager, engineSetup *sync.Mutex, instances []server.InstanceInfo) error {
 	logger := newLogger(cfg.LogLevel)
+	if cfg.Alias != "" {
+		logger = logger.With("alias", cfg.Alias)
+	}
 	logger.Info("starting adapter",
 		"adapter", adapterinfo.Info.Name,
 		"adapter_slug", adapterinfo.Info.Slug,
@@ -68,13 +151,15 @@ func main() {
 	)
 
 	recorder := telemetry.NewRecorder(logger)
+	stopTelemetry := startTelemetryExporter(ctx, cfg, recorder, logger)
+	defer stopTelemetry()
 
 	// STEP 1: Bind port IMMEDIATELY (before loading model)
 	// This allows the manager's readiness check to succeed while model loads in background.
 	lis, err := net.Listen("tcp", cfg.ListenAddr)
 	if err != nil {
 		logger.Error("failed to bind listener", "error", err)
-		os.Exit(1)
+		return err
 	}
 	defer lis.Close()
 	logger.Info("listener bound, port ready", "addr", lis.Addr().String())
@@ -100,15 +185,16 @@ func main() {
 	}()
 	logger.Info("gRPC server started (NOT_SERVING while initializing)")
 
-	// STEP 4: Load model (can take 10+ seconds, but port is already available for readiness checks!)
-	manager, err := models.NewManager(cfg.DataDir, logger)
-	if err != nil {
-		logger.Error("failed to initialise model manager", "error", err)
-		grpcServer.Stop()
-		os.Exit(1)
-	}
+	// STEP 3.5: Bind the REST listener and start serving behind lazy
+	// transcription/translation handlers, the same "port ready before model
+	// ready" shape as STEP 1-3 give the gRPC listener.
+	stopREST, lazyTranscribe, lazyTranslate := startRESTServer(cfg, recorder, logger, instances)
+	defer stopREST()
 
-	eng, modelPath, engineErr := engine.New(cfg, manager, logger)
+	// STEP 4: Load model (can take 10+ seconds, but port is already available for readiness checks!)
+	engineSetup.Lock()
+	eng, modelPath, engineErr := engine.New(cfg, manager, logger, recorder)
+	engineSetup.Unlock()
 	if engineErr != nil {
 		logger.Warn("engine initialised with warnings", "error", engineErr)
 	}
@@ -121,9 +207,20 @@ func main() {
 		}
 	}()
 
-	// STEP 5: Activate the real STT service now that engine is ready
+	// STEP 5: Activate the real STT service and REST handlers now that the
+	// engine is ready
 	realService := server.New(cfg, logger, eng, recorder)
 	lazyService.setServer(realService)
+	if lazyTranscribe != nil {
+		lazyTranscribe.setHandler(server.NewTranscriptionHandler(cfg, logger, eng, recorder))
+		lazyTranslate.setHandler(server.NewTranslationHandler(cfg, logger, eng, recorder))
+	}
+
+	stopWebRTC := startWebRTCServer(cfg, eng, recorder, logger)
+	defer stopWebRTC()
+
+	stopDefaultVariantReload := startDefaultVariantReload(ctx, eng, logger)
+	defer stopDefaultVariantReload()
 
 	healthServer.SetServingStatus("", healthgrpc.HealthCheckResponse_SERVING)
 	healthServer.SetServingStatus(serviceName, healthgrpc.HealthCheckResponse_SERVING)
@@ -151,12 +248,12 @@ func main() {
 	}()
 
 	// STEP 7: Wait for server to finish or error
+	var runErr error
 	select {
-	case err := <-serverErr:
-		logger.Error("gRPC server terminated with error", "error", err)
-		os.Exit(1)
+	case runErr = <-serverErr:
+		logger.Error("gRPC server terminated with error", "error", runErr)
 	case <-ctx.Done():
-		// Normal shutdown via signal
+		// Normal shutdown via signal, or a sibling instance failed.
 	}
 
 	if snapshot := recorder.Snapshot(); snapshot.TotalStreams > 0 {
@@ -171,6 +268,145 @@ func main() {
 	}
 
 	logger.Info("adapter stopped")
+	return runErr
+}
+
+// startTelemetryExporter wires the recorder's MeterProvider to an exporter:
+// an OTLP/HTTP push exporter when cfg.OTLPEndpoint is set, otherwise a
+// Prometheus /metrics handler mounted on cfg.MetricsAddr. It returns a
+// cleanup func the caller should defer.
+func startTelemetryExporter(ctx context.Context, cfg config.Config, recorder *telemetry.Recorder, logger *slog.Logger) func() {
+	if cfg.OTLPEndpoint != "" {
+		exporter := telemetry.NewOTLPExporter(cfg.OTLPEndpoint, recorder.MeterProvider(), recorder.Tracer(), telemetry.OTLPOptions{
+			Headers:     cfg.OTLPHeaders,
+			Insecure:    cfg.OTLPInsecure,
+			Compression: cfg.OTLPCompression,
+		}, logger)
+		exporterCtx, cancel := context.WithCancel(ctx)
+		go exporter.Run(exporterCtx)
+		logger.Info("exporting telemetry via OTLP", "endpoint", cfg.OTLPEndpoint)
+		return cancel
+	}
+
+	metricsLis, err := net.Listen("tcp", cfg.MetricsAddr)
+	if err != nil {
+		logger.Warn("failed to bind metrics listener, telemetry will only be logged", "addr", cfg.MetricsAddr, "error", err)
+		return func() {}
+	}
+	metricsServer := &http.Server{Handler: telemetry.PrometheusHandler(recorder.MeterProvider())}
+	go func() {
+		if err := metricsServer.Serve(metricsLis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("metrics server stopped unexpectedly", "error", err)
+		}
+	}()
+	logger.Info("serving Prometheus metrics", "addr", metricsLis.Addr().String())
+	return func() {
+		_ = metricsServer.Close()
+	}
+}
+
+// startRESTServer mounts lazily-initialized transcription and translation
+// handlers on cfg.RESTAddr so OpenAI Whisper REST clients can transcribe or
+// translate files alongside the gRPC streaming API, plus /v1/instances
+// listing every alias sharing this process. The REST listener is bound and
+// serving immediately, before the engine has finished loading: a request
+// arriving in that window gets 503 with Retry-After until the caller
+// populates the returned handlers' setHandler. It returns a cleanup func the
+// caller should defer, and nil handlers if cfg.RESTEnabled is false.
+func startRESTServer(cfg config.Config, recorder *telemetry.Recorder, logger *slog.Logger, instances []server.InstanceInfo) (stop func(), transcribe, translate *lazyHTTPHandler) {
+	if !cfg.RESTServerEnabled() {
+		logger.Info("REST transcription endpoint disabled by configuration")
+		return func() {}, nil, nil
+	}
+	lis, err := net.Listen("tcp", cfg.RESTAddr)
+	if err != nil {
+		logger.Warn("failed to bind REST listener, /v1/audio/transcriptions will be unavailable", "addr", cfg.RESTAddr, "error", err)
+		return func() {}, nil, nil
+	}
+
+	lazyTranscribe := &lazyHTTPHandler{}
+	lazyTranslate := &lazyHTTPHandler{}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/audio/transcriptions", lazyTranscribe)
+	mux.Handle("/v1/audio/translations", lazyTranslate)
+	mux.Handle("/v1/instances", server.NewInstancesHandler(instances))
+	restServer := &http.Server{Handler: mux}
+	go func() {
+		if err := restServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("REST server stopped unexpectedly", "error", err)
+		}
+	}()
+	logger.Info("serving OpenAI-compatible transcription endpoint (NOT_SERVING while initializing)", "addr", lis.Addr().String())
+	return func() {
+		_ = restServer.Close()
+	}, lazyTranscribe, lazyTranslate
+}
+
+// startWebRTCServer mounts server.WebRTCHandler and server.WebSocketHandler
+// on cfg.WebRTCAddr, so browsers can stream microphone audio straight to the
+// adapter (as Opus-in-WebRTC or Opus-in-WebSocket) without a client-side
+// transcoder. It returns a cleanup func the caller should defer.
+func startWebRTCServer(cfg config.Config, eng engine.Engine, recorder *telemetry.Recorder, logger *slog.Logger) func() {
+	lis, err := net.Listen("tcp", cfg.WebRTCAddr)
+	if err != nil {
+		logger.Warn("failed to bind webrtc listener, /v1/webrtc endpoints will be unavailable", "addr", cfg.WebRTCAddr, "error", err)
+		return func() {}
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/v1/webrtc/offer", server.NewWebRTCHandler(cfg, logger, eng, recorder))
+	mux.Handle("/v1/webrtc/ws", server.NewWebSocketHandler(cfg, logger, eng, recorder))
+	webrtcServer := &http.Server{Handler: mux}
+	go func() {
+		if err := webrtcServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("webrtc server stopped unexpectedly", "error", err)
+		}
+	}()
+	logger.Info("serving WebRTC/WebSocket Opus ingress", "addr", lis.Addr().String())
+	return func() {
+		_ = webrtcServer.Close()
+	}
+}
+
+// startDefaultVariantReload, for engines that implement
+// engine.DefaultVariantSetter (currently only *engine.EnginePool), watches
+// for SIGHUP and re-reads NUPI_MODEL_VARIANT to atomically retarget routing's
+// default variant without a restart. Streams already dispatched to the old
+// default are unaffected: only the next TranscribeSegment/Flush call picks up
+// the swap. It is a no-op for single-model engines. The returned func stops
+// the watch and should be deferred by the caller.
+func startDefaultVariantReload(ctx context.Context, eng engine.Engine, logger *slog.Logger) func() {
+	setter, ok := eng.(engine.DefaultVariantSetter)
+	if !ok {
+		return func() {}
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				variant, ok := os.LookupEnv("NUPI_MODEL_VARIANT")
+				if !ok || strings.TrimSpace(variant) == "" {
+					logger.Warn("SIGHUP received but NUPI_MODEL_VARIANT is unset, ignoring")
+					continue
+				}
+				if err := setter.SetDefaultVariant(variant); err != nil {
+					logger.Warn("failed to swap default model variant", "variant", variant, "error", err)
+					continue
+				}
+				logger.Info("swapped default model variant", "variant", variant)
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sighup)
+	}
 }
 
 func newLogger(level string) *slog.Logger {
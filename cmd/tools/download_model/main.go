@@ -10,7 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/nupi-ai/module-nupi-whisper-local-stt/internal/models"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/models"
 )
 
 func main() {
@@ -11,7 +11,7 @@ import (
 	"os"
 	"time"
 
-	"github.com/nupi-ai/module-nupi-whisper-local-stt/internal/models"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/models"
 )
 
 func main() {
@@ -34,37 +34,41 @@ func main() {
 	client := &http.Client{Timeout: 10 * time.Minute}
 
 	for name, variant := range manifest.Variants {
-		if variant.URL == "" {
-			fmt.Printf("%s: skipping (no URL)\n", name)
+		mirrors := variant.Mirrors()
+		if len(mirrors) == 0 {
+			fmt.Printf("%s: skipping (no URLs)\n", name)
 			continue
 		}
 
-		fmt.Printf("%s: downloading %s...\n", name, variant.URL)
-		resp, err := client.Get(variant.URL)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s: download error: %v\n", name, err)
-			continue
-		}
-		if resp.StatusCode != http.StatusOK {
-			fmt.Fprintf(os.Stderr, "%s: unexpected status %s\n", name, resp.Status)
-			resp.Body.Close()
-			continue
-		}
+		var (
+			firstSHA256 string
+			divergent   bool
+		)
 
-		hasher := sha256.New()
-		written, err := io.Copy(hasher, resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s: read error: %v\n", name, err)
-			continue
+		for i, mirror := range mirrors {
+			sum, size, etag, err := probeMirror(client, mirror)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: mirror %s: %v\n", name, mirror, err)
+				continue
+			}
+			fmt.Printf("%s: mirror %s size=%d sha256=%s etag=%q\n", name, mirror, size, sum, etag)
+
+			if i == 0 {
+				variant.SHA256 = sum
+				variant.SizeBytes = size
+				firstSHA256 = sum
+				continue
+			}
+			if firstSHA256 != "" && sum != firstSHA256 {
+				fmt.Fprintf(os.Stderr, "%s: WARNING mirror %s sha256 %s diverges from primary %s\n", name, mirror, sum, firstSHA256)
+				divergent = true
+			}
 		}
 
-		sum := hex.EncodeToString(hasher.Sum(nil))
-		variant.SHA256 = sum
-		variant.SizeBytes = written
+		if divergent {
+			fmt.Fprintf(os.Stderr, "%s: WARNING mirrors disagree on content; manifest recorded the primary's hash\n", name)
+		}
 		manifest.Variants[name] = variant
-
-		fmt.Printf("%s: size=%d sha256=%s\n", name, written, sum)
 	}
 
 	out, err := os.Create(*manifestPath)
@@ -83,3 +87,28 @@ func main() {
 
 	fmt.Printf("Updated manifest written to %s\n", *manifestPath)
 }
+
+// probeMirror downloads url fully (there's no way to learn the true content
+// hash without reading every byte) and reports its size, sha256, and the
+// ETag the server advertised, which is cheap to compare across mirrors on
+// subsequent runs without a full re-download.
+func probeMirror(client *http.Client, url string) (sha256sum string, size int64, etag string, err error) {
+	fmt.Printf("probing %s...\n", url)
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	hasher := sha256.New()
+	written, err := io.Copy(hasher, resp.Body)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("read: %w", err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), written, resp.Header.Get("ETag"), nil
+}
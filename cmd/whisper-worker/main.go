@@ -0,0 +1,123 @@
+// Command whisper-worker hosts an Engine over gRPC so the adapter can run
+// heavy models on a dedicated (often GPU-equipped) host instead of loading
+// whisper.cpp in its own process. Point an adapter at it by setting
+// NUPI_ADAPTER_BACKEND=grpc://<worker-host>:<worker-port>, or have the
+// adapter spawn and supervise it itself with
+// NUPI_ADAPTER_BACKEND=subprocess://whisper-worker (see
+// internal/engine.SupervisedEngine), in which case ListenAddr is a
+// "unix://<path>" socket rather than a TCP host:port.
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/enginerpc"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/models"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	cfg, err := config.Loader{}.Load()
+	if err != nil {
+		logger.Error("failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+	if cfg.IsRemoteBackend() || cfg.IsSupervisedBackend() {
+		logger.Error("whisper-worker cannot itself delegate to another out-of-process backend", "backend", cfg.Backend)
+		os.Exit(1)
+	}
+
+	manager, err := models.NewManager(cfg.DataDir, logger)
+	if err != nil {
+		logger.Error("failed to initialise model manager", "error", err)
+		os.Exit(1)
+	}
+
+	caps := engine.NativeAvailable()
+	logger.Info("native accelerator capabilities",
+		"native", caps.Native, "metal", caps.Metal, "cuda", caps.CUDA,
+		"core_ml", caps.CoreML, "open_vino", caps.OpenVINO, "blas", caps.BLAS, "vulkan", caps.Vulkan,
+	)
+
+	recorder := telemetry.NewRecorder(logger)
+	eng, modelPath, engineErr := engine.New(cfg, manager, logger, recorder)
+	if engineErr != nil {
+		logger.Warn("engine initialised with warnings", "error", engineErr)
+	}
+	if modelPath != "" {
+		logger.Info("resolved model path", "path", modelPath)
+	}
+	defer func() {
+		if err := eng.Close(); err != nil {
+			logger.Warn("failed to close engine", "error", err)
+		}
+	}()
+
+	lis, err := listen(cfg.ListenAddr)
+	if err != nil {
+		logger.Error("failed to bind listener", "error", err)
+		os.Exit(1)
+	}
+	defer lis.Close()
+
+	grpcServer := grpc.NewServer()
+	enginerpc.RegisterEngineServiceServer(grpcServer, engine.NewGRPCHost(eng))
+
+	// healthServer lets a SupervisedEngine confirm this process is actually
+	// ready to decode (not just that the socket accepts connections) before
+	// treating a respawn as successful, and lets it fail over without
+	// waiting for a TranscribeSegment call to time out.
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			serverErr <- err
+		}
+	}()
+	logger.Info("whisper-worker ready", "addr", lis.Addr().String(), "model_variant", cfg.ModelVariant)
+
+	select {
+	case err := <-serverErr:
+		logger.Error("gRPC server terminated with error", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+		logger.Info("shutdown requested, stopping gRPC server")
+		grpcServer.GracefulStop()
+	}
+
+	logger.Info("whisper-worker stopped")
+}
+
+// listen binds addr, treating a "unix://<path>" addr as a unix socket (used
+// when a SupervisedEngine spawns this binary) and anything else as a TCP
+// host:port.
+func listen(addr string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, "unix://"); ok {
+		if err := os.RemoveAll(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", addr)
+}
@@ -1,6 +1,9 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const (
 	// DefaultListenAddr is used when the adapter runner does not inject an explicit address.
@@ -9,8 +12,127 @@ const (
 	DefaultLanguage   = "auto"
 	DefaultLogLevel   = "info"
 	DefaultDataDir    = "data"
+	// DefaultBackend selects the in-process native/stub engine selection logic.
+	DefaultBackend = "native"
+
+	// DefaultMetricsAddr is where the Prometheus /metrics handler listens
+	// when no OTLP exporter endpoint is configured.
+	DefaultMetricsAddr = "127.0.0.1:9464"
+
+	// DefaultOTLPCompression leaves OTLP export requests uncompressed when
+	// OTLPCompression is not set.
+	DefaultOTLPCompression = "none"
+
+	// DefaultRESTAddr is where the OpenAI-compatible
+	// POST /v1/audio/transcriptions handler listens.
+	DefaultRESTAddr = "127.0.0.1:9465"
+
+	// DefaultWebRTCAddr is where the WebRTC/WebSocket Opus ingress
+	// (POST /v1/webrtc/offer and the websocket frame endpoint) listens.
+	DefaultWebRTCAddr = "127.0.0.1:9466"
+
+	// VADModeFixed submits a whisper decode call for every fixed-size chunk
+	// handed to the engine, matching the adapter's original chunking.
+	VADModeFixed = "fixed"
+	// VADModeEnergy buffers audio and only decodes at utterance boundaries
+	// detected by internal/engine/segmenter.
+	VADModeEnergy = "energy"
+	// DefaultVADMode keeps the original fixed-chunk behaviour until a caller
+	// opts into segmentation.
+	DefaultVADMode = VADModeFixed
+
+	// DefaultMinSilenceMs is how long trailing silence must last before a
+	// segmenter.Segmenter closes an in-progress utterance.
+	DefaultMinSilenceMs = 500
+	// DefaultMaxUtteranceMs forces an utterance boundary even without
+	// detected silence.
+	DefaultMaxUtteranceMs = 15000
+	// DefaultPromptContextChars bounds how much of the previous final
+	// transcript is carried forward as whisper prompt context.
+	DefaultPromptContextChars = 200
+
+	// DefaultStableThreshold is how many consecutive sliding-window
+	// hypotheses must agree on a prefix before engine.Stabilizer confirms
+	// it, when StreamStabilize is enabled.
+	DefaultStableThreshold = 2
+
+	// VADGateBackendNone hands every chunk a stream receives straight to
+	// the engine, matching the adapter's original always-decode behaviour.
+	VADGateBackendNone = "none"
+	// VADGateBackendEnergy gates TranscribeSegment calls on vad.EnergyDetector,
+	// a dependency-free RMS energy and zero-crossing-rate front end.
+	VADGateBackendEnergy = "energy"
+	// VADGateBackendSilero gates TranscribeSegment calls on the Silero VAD
+	// ONNX model (internal/vad's "silero" build tag).
+	VADGateBackendSilero = "silero"
+	// DefaultVADGateBackend keeps the adapter's original always-decode
+	// behaviour until a caller opts into gating.
+	DefaultVADGateBackend = VADGateBackendNone
+
+	// DefaultVADGateMinSpeechMs is how long audio must be classified as
+	// speech before vad.Detector considers a segment to have started.
+	DefaultVADGateMinSpeechMs = 200
+	// DefaultVADGateMinSilenceMs is how long trailing silence must last
+	// before vad.Detector closes out a detected speech segment.
+	DefaultVADGateMinSilenceMs = 300
+	// DefaultVADGatePreRollMs is how much audio immediately preceding a
+	// detected onset vad.Detector includes in the segment.
+	DefaultVADGatePreRollMs = 200
+
+	// AcceleratorAuto lets the native engine pick the best accelerator it
+	// was compiled with for the current host.
+	AcceleratorAuto = "auto"
+	// AcceleratorCPU forces plain CPU inference, disabling GPU offload.
+	AcceleratorCPU = "cpu"
+	// AcceleratorMetal selects Apple's Metal backend (darwin/arm64).
+	AcceleratorMetal = "metal"
+	// AcceleratorCUDA selects the CUDA backend.
+	AcceleratorCUDA = "cuda"
+	// AcceleratorCoreML selects the Core ML backend, which requires a
+	// .mlmodelc sidecar alongside the ggml model.
+	AcceleratorCoreML = "coreml"
+	// AcceleratorOpenVINO selects the OpenVINO backend, which requires an
+	// OpenVINO IR sidecar alongside the ggml model.
+	AcceleratorOpenVINO = "openvino"
+	// AcceleratorBLAS forces CPU inference through a BLAS backend (ggml's
+	// OpenBLAS or a vendor-provided cBLAS), selected via
+	// NativeOptions.BLASLibrary instead of the plain reference CPU path.
+	AcceleratorBLAS = "blas"
+	// AcceleratorVulkan selects the Vulkan compute backend, useful on Linux
+	// boxes with a non-NVIDIA GPU that CUDA can't target.
+	AcceleratorVulkan = "vulkan"
+	// DefaultAccelerator leaves accelerator selection to the native engine.
+	DefaultAccelerator = AcceleratorAuto
+
+	// DefaultMaxResidentModels bounds how many models an EnginePool keeps
+	// loaded at once when Models is non-empty; least-recently-used models
+	// beyond this count are evicted before a new one is loaded.
+	DefaultMaxResidentModels = 1
+
+	// DefaultMaxResidentBytes leaves an EnginePool's resident set unbounded
+	// by model file size, relying on MaxResidentModels alone.
+	DefaultMaxResidentBytes = 0
 )
 
+// ModelPoolEntry describes one model an EnginePool can load on demand,
+// decoded from the NUPI_ADAPTER_MODELS JSON array.
+type ModelPoolEntry struct {
+	// Variant is the manifest/model-manager variant name (e.g. "tiny.en",
+	// "base", "large-v3").
+	Variant string `json:"variant"`
+	// Path, when set, overrides the manifest lookup with an explicit model
+	// file, mirroring Config.ModelPath.
+	Path string `json:"path"`
+	// Languages lists the BCP-47/ISO-639-1 tags this entry should be routed
+	// for. An entry with no Languages is only reachable via explicit
+	// Options.Variant selection.
+	Languages []string `json:"languages"`
+	Threads   *int     `json:"threads"`
+	UseGPU    *bool    `json:"use_gpu"`
+	// FlashAttention mirrors Config.FlashAttention, per entry.
+	FlashAttention *bool `json:"flash_attention"`
+}
+
 // Config captures bootstrap configuration extracted from environment variables
 // or injected JSON payload (`NUPI_ADAPTER_CONFIG`).
 type Config struct {
@@ -24,6 +146,159 @@ type Config struct {
 	UseGPU         *bool
 	FlashAttention *bool
 	Threads        *int
+	// Backend selects the engine implementation: "native" (whisper.cpp in
+	// process), "stub", "grpc://host:port" to delegate transcription to an
+	// already-running out-of-process engine server, or
+	// "subprocess://[path-to-whisper-worker]" to have the adapter spawn and
+	// supervise that engine server itself over a unix socket (see
+	// SupervisedEngine). An empty path after "subprocess://" resolves
+	// "whisper-worker" on PATH.
+	Backend string
+	// VADMode selects how incoming audio is split into decode calls:
+	// VADModeFixed (legacy fixed-size chunking) or VADModeEnergy (buffer
+	// until an utterance boundary is detected by internal/engine/segmenter).
+	VADMode string
+	// MinSilenceMs is how long trailing silence must last, in VADModeEnergy,
+	// before an in-progress utterance is closed out as a final.
+	MinSilenceMs int
+	// MaxUtteranceMs forces an utterance boundary in VADModeEnergy even
+	// without detected silence.
+	MaxUtteranceMs int
+	// PromptContextChars bounds how much of the previous final transcript is
+	// carried forward as prompt context for the next utterance.
+	PromptContextChars int
+	// VADGateBackend selects the internal/vad.Detector that gates
+	// Server.StreamTranscription's calls into engine.TranscribeSegment on
+	// voice activity: VADGateBackendNone (the default, gate disabled),
+	// VADGateBackendEnergy, or VADGateBackendSilero. This is independent of
+	// VADMode, which buffers audio into whole utterances inside the engine
+	// layer rather than deciding whether to decode a chunk at all.
+	VADGateBackend string
+	// VADGateMinSpeechMs is how long audio must be classified as speech
+	// before the VADGateBackend detector considers a segment to have
+	// started.
+	VADGateMinSpeechMs int
+	// VADGateMinSilenceMs is how long trailing silence must last before the
+	// VADGateBackend detector closes out a detected speech segment.
+	VADGateMinSilenceMs int
+	// VADGatePreRollMs is how much audio immediately preceding a detected
+	// onset the VADGateBackend detector includes in the segment.
+	VADGatePreRollMs int
+	// StreamStabilize wraps each stream's transcripts in an
+	// engine.Stabilizer so sliding-window partial hypotheses are only sent
+	// to the caller as final once a prefix has agreed across
+	// StableThreshold consecutive windows, instead of trusting the
+	// engine's own Final flag on every result.
+	StreamStabilize bool
+	// StableThreshold is how many consecutive windows must agree on a
+	// prefix before StreamStabilize confirms it. Only meaningful when
+	// StreamStabilize is enabled.
+	StableThreshold int
+	// Accelerator selects the compute backend the native engine should
+	// negotiate at runtime: AcceleratorAuto, AcceleratorCPU,
+	// AcceleratorMetal, AcceleratorCUDA, AcceleratorCoreML,
+	// AcceleratorOpenVINO, AcceleratorBLAS, or AcceleratorVulkan.
+	Accelerator string
+	// DeviceIndex pins which GPU the native engine initialises against when
+	// Accelerator selects a GPU backend, for hosts with more than one
+	// device. Nil leaves device selection at the backend's default.
+	DeviceIndex *int
+	// BLASLibrary is the path to the BLAS shared library the native engine
+	// should load when Accelerator is AcceleratorBLAS. Empty uses whichever
+	// BLAS implementation the binary was linked against at build time.
+	BLASLibrary string
+	// WordTimestamps enables whisper's DTW token-level alignment so
+	// engine.Result carries per-word timing.
+	WordTimestamps bool
+	// Diarization enables whisper.cpp's experimental TinyDiarize mode
+	// (engine.NativeOptions.TinyDiarize), marking the word after which a
+	// speaker turn was detected. Has no effect unless WordTimestamps is
+	// also enabled, since speaker turns are reported per-word.
+	Diarization bool
+	// OTLPEndpoint, when set, is the base URL of an OTLP/HTTP collector
+	// (env NUPI_OTEL_EXPORTER_OTLP_ENDPOINT) that telemetry.OTLPExporter
+	// pushes metrics to. When empty, the adapter falls back to serving
+	// Prometheus-format metrics on MetricsAddr instead.
+	OTLPEndpoint string
+	// OTLPHeaders are added to every OTLP export request, typically for
+	// collector auth (env NUPI_OTEL_EXPORTER_OTLP_HEADERS, a comma-separated
+	// key=value list matching the upstream OTEL_EXPORTER_OTLP_HEADERS
+	// convention).
+	OTLPHeaders map[string]string
+	// OTLPInsecure skips TLS certificate verification against an https
+	// OTLPEndpoint (env NUPI_OTEL_EXPORTER_OTLP_INSECURE).
+	OTLPInsecure bool
+	// OTLPCompression selects the OTLP export request body encoding: "gzip",
+	// "zstd", or "none"/"" (env NUPI_OTEL_EXPORTER_OTLP_COMPRESSION). See
+	// telemetry.OTLPCompressionGzip and friends.
+	OTLPCompression string
+	// MetricsAddr is the listen address for the Prometheus /metrics
+	// fallback handler, used when OTLPEndpoint is empty.
+	MetricsAddr string
+	// RESTAddr is the listen address for the OpenAI-compatible
+	// POST /v1/audio/transcriptions handler.
+	RESTAddr string
+	// RESTEnabled toggles the REST listener started on RESTAddr. Nil (the
+	// env/JSON default) behaves as enabled, matching the original
+	// always-on behaviour; set to false to run gRPC-only.
+	RESTEnabled *bool
+	// WebRTCAddr is the listen address for the WebRTC/WebSocket Opus
+	// ingress, so browsers can stream microphone audio directly to the
+	// adapter without a client-side transcoder.
+	WebRTCAddr string
+	// Models, when non-empty, configures a multi-model EnginePool instead of
+	// a single engine for ModelVariant: each entry is lazy-loaded through
+	// models.Manager and streams are routed to the entry whose Languages
+	// include the resolved language, falling back to ModelVariant as the
+	// pool's default. Populated from the NUPI_ADAPTER_MODELS JSON array.
+	Models []ModelPoolEntry
+	// MaxResidentModels bounds how many Models entries an EnginePool keeps
+	// loaded simultaneously, evicting the least-recently-used one first.
+	MaxResidentModels int
+	// MaxResidentBytes additionally bounds an EnginePool's resident set by
+	// the total on-disk size of its loaded models' ggml files, evicting the
+	// least-recently-used entry first whenever loading another would exceed
+	// the budget. Zero (the default) leaves the resident set bounded by
+	// MaxResidentModels alone.
+	MaxResidentBytes int64
+	// Alias names this instance when Loader.LoadAll fans NUPI_ADAPTER_CONFIG
+	// out into several side-by-side instances (see cmd/adapter). Every log
+	// line and metric the instance emits is tagged alias=<name>. Empty for a
+	// single-instance configuration.
+	Alias string
+}
+
+// IsRemoteBackend reports whether Backend names an out-of-process engine to
+// dial rather than one of the in-process implementations.
+func (c Config) IsRemoteBackend() bool {
+	return strings.HasPrefix(c.Backend, "grpc://")
+}
+
+// BackendAddress strips the "grpc://" scheme from Backend, returning the
+// dial target for an out-of-process engine. It is only meaningful when
+// IsRemoteBackend reports true.
+func (c Config) BackendAddress() string {
+	return strings.TrimPrefix(c.Backend, "grpc://")
+}
+
+// IsSupervisedBackend reports whether Backend names an engine server the
+// adapter should spawn and supervise itself, rather than dial an
+// already-running one (IsRemoteBackend) or load in process.
+func (c Config) IsSupervisedBackend() bool {
+	return strings.HasPrefix(c.Backend, "subprocess://")
+}
+
+// SupervisedBinary strips the "subprocess://" scheme from Backend, returning
+// the engine-server binary to spawn. Empty means "whisper-worker", resolved
+// from PATH. It is only meaningful when IsSupervisedBackend reports true.
+func (c Config) SupervisedBinary() string {
+	return strings.TrimPrefix(c.Backend, "subprocess://")
+}
+
+// RESTServerEnabled reports whether the OpenAI-compatible REST listener
+// should be started, honouring RESTEnabled's "unset means enabled" default.
+func (c Config) RESTServerEnabled() bool {
+	return c.RESTEnabled == nil || *c.RESTEnabled
 }
 
 // Validate applies defaults and raises an error when required fields are
@@ -44,5 +319,53 @@ func (c *Config) Validate() error {
 	if c.DataDir == "" {
 		c.DataDir = DefaultDataDir
 	}
+	if c.Backend == "" {
+		c.Backend = DefaultBackend
+	}
+	if c.VADMode == "" {
+		c.VADMode = DefaultVADMode
+	}
+	if c.MinSilenceMs == 0 {
+		c.MinSilenceMs = DefaultMinSilenceMs
+	}
+	if c.MaxUtteranceMs == 0 {
+		c.MaxUtteranceMs = DefaultMaxUtteranceMs
+	}
+	if c.PromptContextChars == 0 {
+		c.PromptContextChars = DefaultPromptContextChars
+	}
+	if c.StableThreshold == 0 {
+		c.StableThreshold = DefaultStableThreshold
+	}
+	if c.VADGateBackend == "" {
+		c.VADGateBackend = DefaultVADGateBackend
+	}
+	if c.VADGateMinSpeechMs == 0 {
+		c.VADGateMinSpeechMs = DefaultVADGateMinSpeechMs
+	}
+	if c.VADGateMinSilenceMs == 0 {
+		c.VADGateMinSilenceMs = DefaultVADGateMinSilenceMs
+	}
+	if c.VADGatePreRollMs == 0 {
+		c.VADGatePreRollMs = DefaultVADGatePreRollMs
+	}
+	if c.Accelerator == "" {
+		c.Accelerator = DefaultAccelerator
+	}
+	if c.MetricsAddr == "" {
+		c.MetricsAddr = DefaultMetricsAddr
+	}
+	if c.RESTAddr == "" {
+		c.RESTAddr = DefaultRESTAddr
+	}
+	if c.WebRTCAddr == "" {
+		c.WebRTCAddr = DefaultWebRTCAddr
+	}
+	if c.MaxResidentModels == 0 {
+		c.MaxResidentModels = DefaultMaxResidentModels
+	}
+	if c.OTLPCompression == "" {
+		c.OTLPCompression = DefaultOTLPCompression
+	}
 	return nil
 }
@@ -32,47 +32,273 @@ func (l Loader) Load() (Config, error) {
 		}
 	}
 
-	overrideString(l.Lookup, "NUPI_ADAPTER_LISTEN_ADDR", &cfg.ListenAddr)
-	overrideString(l.Lookup, "NUPI_LOG_LEVEL", &cfg.LogLevel)
-	overrideString(l.Lookup, "NUPI_MODEL_VARIANT", &cfg.ModelVariant)
-	overrideString(l.Lookup, "NUPI_LANGUAGE_HINT", &cfg.Language)
-	overrideString(l.Lookup, "NUPI_ADAPTER_DATA_DIR", &cfg.DataDir)
-	overrideString(l.Lookup, "NUPI_MODEL_PATH", &cfg.ModelPath)
-	overrideBool(l.Lookup, "NUPI_ADAPTER_USE_STUB_ENGINE", &cfg.UseStubEngine)
-	if value, ok := l.Lookup("WHISPERCPP_USE_GPU"); ok {
+	if err := applyEnvOverrides(l.Lookup, &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// LoadAll is Load's multi-instance counterpart: it also accepts
+// NUPI_ADAPTER_CONFIG as a JSON array of objects, each describing one named
+// instance that cmd/adapter runs side by side in the same process (see
+// config.Config.Alias). Every entry must set a unique, non-blank "alias";
+// env var overrides apply to each instance as a shared baseline before its
+// own JSON fields are layered on top, so a single alias can still diverge on
+// listen_addr, model_variant, language, threads, use_gpu, and
+// flash_attention without a global env var clobbering it.
+//
+// When NUPI_ADAPTER_CONFIG is absent or a single JSON object, LoadAll
+// behaves exactly like Load and returns a one-element slice.
+func (l Loader) LoadAll() ([]Config, error) {
+	if l.Lookup == nil {
+		l.Lookup = os.LookupEnv
+	}
+
+	raw, ok := l.Lookup("NUPI_ADAPTER_CONFIG")
+	if !ok || !isJSONArray(raw) {
+		cfg, err := l.Load()
+		if err != nil {
+			return nil, err
+		}
+		return []Config{cfg}, nil
+	}
+
+	var rawEntries []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &rawEntries); err != nil {
+		return nil, fmt.Errorf("config: decode NUPI_ADAPTER_CONFIG array: %w", err)
+	}
+	if len(rawEntries) == 0 {
+		return nil, fmt.Errorf("config: NUPI_ADAPTER_CONFIG array must not be empty")
+	}
+
+	seenAlias := make(map[string]bool, len(rawEntries))
+	seenListenAddr := make(map[string]bool, len(rawEntries))
+	seenRESTAddr := make(map[string]bool, len(rawEntries))
+	seenWebRTCAddr := make(map[string]bool, len(rawEntries))
+	configs := make([]Config, 0, len(rawEntries))
+	for i, entry := range rawEntries {
+		cfg := Config{
+			ListenAddr: DefaultListenAddr,
+			DataDir:    DefaultDataDir,
+		}
+		if err := applyEnvOverrides(l.Lookup, &cfg); err != nil {
+			return nil, fmt.Errorf("config: instance %d: %w", i, err)
+		}
+		// DataDir (the model cache) is shared across every alias in the
+		// process, not one of the fields an instance's JSON object may
+		// override, so cmd/adapter can safely use a single models.Manager.
+		sharedDataDir := cfg.DataDir
+		if err := applyJSON(string(entry), &cfg); err != nil {
+			return nil, fmt.Errorf("config: instance %d: %w", i, err)
+		}
+		cfg.DataDir = sharedDataDir
+
+		var aliasPayload struct {
+			Alias      string  `json:"alias"`
+			WebRTCAddr *string `json:"webrtc_addr"`
+		}
+		if err := json.Unmarshal(entry, &aliasPayload); err != nil {
+			return nil, fmt.Errorf("config: instance %d: decode alias: %w", i, err)
+		}
+		cfg.Alias = strings.TrimSpace(aliasPayload.Alias)
+		if cfg.Alias == "" {
+			return nil, fmt.Errorf("config: instance %d: alias is required", i)
+		}
+		if seenAlias[cfg.Alias] {
+			return nil, fmt.Errorf("config: duplicate alias %q", cfg.Alias)
+		}
+		seenAlias[cfg.Alias] = true
+
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("config: instance %q: %w", cfg.Alias, err)
+		}
+		if seenListenAddr[cfg.ListenAddr] {
+			return nil, fmt.Errorf("config: instance %q: listen_addr %q is already used by another instance", cfg.Alias, cfg.ListenAddr)
+		}
+		seenListenAddr[cfg.ListenAddr] = true
+		if seenRESTAddr[cfg.RESTAddr] {
+			return nil, fmt.Errorf("config: instance %q: rest_addr %q is already used by another instance", cfg.Alias, cfg.RESTAddr)
+		}
+		seenRESTAddr[cfg.RESTAddr] = true
+		// Unlike listen_addr/rest_addr, webrtc_addr falls back to the same
+		// DefaultWebRTCAddr for every instance that doesn't set it, and its
+		// listener already degrades gracefully on a bind failure (see
+		// cmd/adapter's startWebRTCServer), so only reject a collision the
+		// operator actually asked for by setting webrtc_addr explicitly.
+		if aliasPayload.WebRTCAddr != nil {
+			if seenWebRTCAddr[cfg.WebRTCAddr] {
+				return nil, fmt.Errorf("config: instance %q: webrtc_addr %q is already used by another instance", cfg.Alias, cfg.WebRTCAddr)
+			}
+			seenWebRTCAddr[cfg.WebRTCAddr] = true
+		}
+
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// isJSONArray reports whether raw's first non-whitespace byte opens a JSON
+// array, distinguishing LoadAll's multi-instance form of NUPI_ADAPTER_CONFIG
+// from the existing single-object form.
+func isJSONArray(raw string) bool {
+	return strings.HasPrefix(strings.TrimSpace(raw), "[")
+}
+
+// applyEnvOverrides layers the adapter's environment variables onto cfg,
+// shared by Load and LoadAll.
+func applyEnvOverrides(lookup func(string) (string, bool), cfg *Config) error {
+	overrideString(lookup, "NUPI_ADAPTER_LISTEN_ADDR", &cfg.ListenAddr)
+	overrideString(lookup, "NUPI_LOG_LEVEL", &cfg.LogLevel)
+	overrideString(lookup, "NUPI_MODEL_VARIANT", &cfg.ModelVariant)
+	overrideString(lookup, "NUPI_LANGUAGE_HINT", &cfg.Language)
+	overrideString(lookup, "NUPI_ADAPTER_DATA_DIR", &cfg.DataDir)
+	overrideString(lookup, "NUPI_MODEL_PATH", &cfg.ModelPath)
+	overrideString(lookup, "NUPI_ADAPTER_BACKEND", &cfg.Backend)
+	overrideString(lookup, "NUPI_VAD_MODE", &cfg.VADMode)
+	overrideString(lookup, "NUPI_VAD_GATE_BACKEND", &cfg.VADGateBackend)
+	overrideString(lookup, "NUPI_ACCELERATOR", &cfg.Accelerator)
+	overrideString(lookup, "WHISPERCPP_BLAS_LIBRARY", &cfg.BLASLibrary)
+	if value, ok := lookup("WHISPERCPP_DEVICE_INDEX"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			device := parsed
+			cfg.DeviceIndex = &device
+		}
+	}
+	overrideString(lookup, "NUPI_OTEL_EXPORTER_OTLP_ENDPOINT", &cfg.OTLPEndpoint)
+	overrideString(lookup, "NUPI_OTEL_EXPORTER_OTLP_COMPRESSION", &cfg.OTLPCompression)
+	overrideBool(lookup, "NUPI_OTEL_EXPORTER_OTLP_INSECURE", &cfg.OTLPInsecure)
+	if value, ok := lookup("NUPI_OTEL_EXPORTER_OTLP_HEADERS"); ok && strings.TrimSpace(value) != "" {
+		headers, err := parseOTLPHeaders(value)
+		if err != nil {
+			return fmt.Errorf("config: decode NUPI_OTEL_EXPORTER_OTLP_HEADERS: %w", err)
+		}
+		cfg.OTLPHeaders = headers
+	}
+	overrideString(lookup, "NUPI_METRICS_ADDR", &cfg.MetricsAddr)
+	overrideString(lookup, "NUPI_REST_ADDR", &cfg.RESTAddr)
+	overrideString(lookup, "NUPI_WEBRTC_ADDR", &cfg.WebRTCAddr)
+	overrideBool(lookup, "NUPI_ADAPTER_USE_STUB_ENGINE", &cfg.UseStubEngine)
+	overrideBool(lookup, "NUPI_WORD_TIMESTAMPS", &cfg.WordTimestamps)
+	overrideBool(lookup, "NUPI_DIARIZATION", &cfg.Diarization)
+	if value, ok := lookup("NUPI_VAD_MIN_SILENCE_MS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.MinSilenceMs = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_VAD_MAX_UTTERANCE_MS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.MaxUtteranceMs = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_VAD_PROMPT_CONTEXT_CHARS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.PromptContextChars = parsed
+		}
+	}
+	overrideBool(lookup, "NUPI_STREAM_STABILIZE", &cfg.StreamStabilize)
+	if value, ok := lookup("NUPI_STABLE_THRESHOLD"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.StableThreshold = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_VAD_GATE_MIN_SPEECH_MS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.VADGateMinSpeechMs = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_VAD_GATE_MIN_SILENCE_MS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.VADGateMinSilenceMs = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_VAD_GATE_PRE_ROLL_MS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.VADGatePreRollMs = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_ADAPTER_MODELS"); ok && strings.TrimSpace(value) != "" {
+		var entries []ModelPoolEntry
+		if err := json.Unmarshal([]byte(value), &entries); err != nil {
+			return fmt.Errorf("config: decode NUPI_ADAPTER_MODELS: %w", err)
+		}
+		cfg.Models = entries
+	}
+	if value, ok := lookup("NUPI_ADAPTER_MAX_RESIDENT_MODELS"); ok {
+		if parsed, err := parseInt(value); err == nil {
+			cfg.MaxResidentModels = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_ADAPTER_MAX_RESIDENT_BYTES"); ok {
+		if parsed, err := parseInt64(value); err == nil {
+			cfg.MaxResidentBytes = parsed
+		}
+	}
+	if value, ok := lookup("NUPI_REST_ENABLED"); ok {
+		if parsed, err := parseBool(value); err == nil {
+			assignBoolPtr(&cfg.RESTEnabled, parsed)
+		}
+	}
+	if value, ok := lookup("WHISPERCPP_USE_GPU"); ok {
 		if parsed, err := parseBool(value); err == nil {
 			assignBoolPtr(&cfg.UseGPU, parsed)
 		}
 	}
-	if value, ok := l.Lookup("WHISPERCPP_FLASH_ATTENTION"); ok {
+	if value, ok := lookup("WHISPERCPP_FLASH_ATTENTION"); ok {
 		if parsed, err := parseBool(value); err == nil {
 			assignBoolPtr(&cfg.FlashAttention, parsed)
 		}
 	}
-	if value, ok := l.Lookup("WHISPERCPP_THREADS"); ok {
+	if value, ok := lookup("WHISPERCPP_THREADS"); ok {
 		if parsed, err := parseInt(value); err == nil {
 			assignIntPtr(&cfg.Threads, parsed)
 		}
 	}
-
-	if err := cfg.Validate(); err != nil {
-		return Config{}, err
-	}
-	return cfg, nil
+	return nil
 }
 
 func applyJSON(raw string, cfg *Config) error {
 	type jsonConfig struct {
-		ListenAddr     string `json:"listen_addr"`
-		ModelVariant   string `json:"model_variant"`
-		Language       string `json:"language"`
-		LogLevel       string `json:"log_level"`
-		DataDir        string `json:"data_dir"`
-		ModelPath      string `json:"model_path"`
-		UseStubEngine  *bool  `json:"use_stub_engine"`
-		UseGPU         *bool  `json:"use_gpu"`
-		FlashAttention *bool  `json:"flash_attention"`
-		Threads        *int   `json:"threads"`
+		ListenAddr          string            `json:"listen_addr"`
+		ModelVariant        string            `json:"model_variant"`
+		Language            string            `json:"language"`
+		LogLevel            string            `json:"log_level"`
+		DataDir             string            `json:"data_dir"`
+		ModelPath           string            `json:"model_path"`
+		Backend             string            `json:"backend"`
+		VADMode             string            `json:"vad_mode"`
+		VADGateBackend      string            `json:"vad_gate_backend"`
+		VADGateMinSpeechMs  int               `json:"vad_gate_min_speech_ms"`
+		VADGateMinSilenceMs int               `json:"vad_gate_min_silence_ms"`
+		VADGatePreRollMs    int               `json:"vad_gate_pre_roll_ms"`
+		Accelerator         string            `json:"accelerator"`
+		DeviceIndex         *int              `json:"device_index"`
+		BLASLibrary         string            `json:"blas_library"`
+		OTLPEndpoint        string            `json:"otlp_endpoint"`
+		OTLPHeaders         map[string]string `json:"otlp_headers"`
+		OTLPInsecure        *bool             `json:"otlp_insecure"`
+		OTLPCompression     string            `json:"otlp_compression"`
+		MetricsAddr         string            `json:"metrics_addr"`
+		RESTAddr            string            `json:"rest_addr"`
+		RESTEnabled         *bool             `json:"rest_enabled"`
+		WebRTCAddr          string            `json:"webrtc_addr"`
+		Models              []ModelPoolEntry  `json:"models"`
+		MaxResidentModels   int               `json:"max_resident_models"`
+		MaxResidentBytes    int64             `json:"max_resident_bytes"`
+		MinSilenceMs        int               `json:"min_silence_ms"`
+		MaxUtteranceMs      int               `json:"max_utterance_ms"`
+		PromptContextChars  int               `json:"prompt_context_chars"`
+		WordTimestamps      *bool             `json:"word_timestamps"`
+		Diarization         *bool             `json:"diarization"`
+		StreamStabilize     *bool             `json:"stream_stabilize"`
+		StableThreshold     int               `json:"stable_threshold"`
+		UseStubEngine       *bool             `json:"use_stub_engine"`
+		UseGPU              *bool             `json:"use_gpu"`
+		FlashAttention      *bool             `json:"flash_attention"`
+		Threads             *int              `json:"threads"`
 	}
 	var payload jsonConfig
 	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
@@ -96,6 +322,87 @@ func applyJSON(raw string, cfg *Config) error {
 	if payload.ModelPath != "" {
 		cfg.ModelPath = payload.ModelPath
 	}
+	if payload.Backend != "" {
+		cfg.Backend = payload.Backend
+	}
+	if payload.VADMode != "" {
+		cfg.VADMode = payload.VADMode
+	}
+	if payload.VADGateBackend != "" {
+		cfg.VADGateBackend = payload.VADGateBackend
+	}
+	if payload.VADGateMinSpeechMs != 0 {
+		cfg.VADGateMinSpeechMs = payload.VADGateMinSpeechMs
+	}
+	if payload.VADGateMinSilenceMs != 0 {
+		cfg.VADGateMinSilenceMs = payload.VADGateMinSilenceMs
+	}
+	if payload.VADGatePreRollMs != 0 {
+		cfg.VADGatePreRollMs = payload.VADGatePreRollMs
+	}
+	if payload.Accelerator != "" {
+		cfg.Accelerator = payload.Accelerator
+	}
+	if payload.DeviceIndex != nil {
+		cfg.DeviceIndex = payload.DeviceIndex
+	}
+	if payload.BLASLibrary != "" {
+		cfg.BLASLibrary = payload.BLASLibrary
+	}
+	if payload.OTLPEndpoint != "" {
+		cfg.OTLPEndpoint = payload.OTLPEndpoint
+	}
+	if len(payload.OTLPHeaders) > 0 {
+		cfg.OTLPHeaders = payload.OTLPHeaders
+	}
+	if payload.OTLPInsecure != nil {
+		cfg.OTLPInsecure = *payload.OTLPInsecure
+	}
+	if payload.OTLPCompression != "" {
+		cfg.OTLPCompression = payload.OTLPCompression
+	}
+	if payload.MetricsAddr != "" {
+		cfg.MetricsAddr = payload.MetricsAddr
+	}
+	if payload.RESTAddr != "" {
+		cfg.RESTAddr = payload.RESTAddr
+	}
+	if payload.RESTEnabled != nil {
+		assignBoolPtr(&cfg.RESTEnabled, *payload.RESTEnabled)
+	}
+	if payload.WebRTCAddr != "" {
+		cfg.WebRTCAddr = payload.WebRTCAddr
+	}
+	if len(payload.Models) > 0 {
+		cfg.Models = payload.Models
+	}
+	if payload.MaxResidentModels != 0 {
+		cfg.MaxResidentModels = payload.MaxResidentModels
+	}
+	if payload.MaxResidentBytes != 0 {
+		cfg.MaxResidentBytes = payload.MaxResidentBytes
+	}
+	if payload.MinSilenceMs != 0 {
+		cfg.MinSilenceMs = payload.MinSilenceMs
+	}
+	if payload.MaxUtteranceMs != 0 {
+		cfg.MaxUtteranceMs = payload.MaxUtteranceMs
+	}
+	if payload.PromptContextChars != 0 {
+		cfg.PromptContextChars = payload.PromptContextChars
+	}
+	if payload.WordTimestamps != nil {
+		cfg.WordTimestamps = *payload.WordTimestamps
+	}
+	if payload.Diarization != nil {
+		cfg.Diarization = *payload.Diarization
+	}
+	if payload.StreamStabilize != nil {
+		cfg.StreamStabilize = *payload.StreamStabilize
+	}
+	if payload.StableThreshold != 0 {
+		cfg.StableThreshold = payload.StableThreshold
+	}
 	if payload.UseStubEngine != nil {
 		cfg.UseStubEngine = *payload.UseStubEngine
 	}
@@ -155,6 +462,37 @@ func parseInt(value string) (int, error) {
 	return parsed, nil
 }
 
+func parseInt64(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0, fmt.Errorf("empty")
+	}
+	parsed, err := strconv.ParseInt(trimmed, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return parsed, nil
+}
+
+// parseOTLPHeaders parses the comma-separated key=value list accepted by
+// NUPI_OTEL_EXPORTER_OTLP_HEADERS, matching the upstream
+// OTEL_EXPORTER_OTLP_HEADERS convention.
+func parseOTLPHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return headers, nil
+}
+
 func assignBoolPtr(target **bool, value bool) {
 	v := value
 	*target = &v
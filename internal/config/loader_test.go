@@ -42,6 +42,219 @@ func TestLoaderDefaults(t *testing.T) {
 	if cfg.Threads != nil {
 		t.Fatalf("expected threads default (nil), got %v", *cfg.Threads)
 	}
+	if cfg.Backend != config.DefaultBackend {
+		t.Fatalf("expected backend %q, got %q", config.DefaultBackend, cfg.Backend)
+	}
+	if cfg.IsRemoteBackend() {
+		t.Fatalf("expected default backend to not be remote")
+	}
+	if cfg.VADMode != config.DefaultVADMode {
+		t.Fatalf("expected vad mode %q, got %q", config.DefaultVADMode, cfg.VADMode)
+	}
+	if cfg.MinSilenceMs != config.DefaultMinSilenceMs {
+		t.Fatalf("expected min silence ms %d, got %d", config.DefaultMinSilenceMs, cfg.MinSilenceMs)
+	}
+	if cfg.MaxUtteranceMs != config.DefaultMaxUtteranceMs {
+		t.Fatalf("expected max utterance ms %d, got %d", config.DefaultMaxUtteranceMs, cfg.MaxUtteranceMs)
+	}
+	if cfg.PromptContextChars != config.DefaultPromptContextChars {
+		t.Fatalf("expected prompt context chars %d, got %d", config.DefaultPromptContextChars, cfg.PromptContextChars)
+	}
+	if cfg.Accelerator != config.DefaultAccelerator {
+		t.Fatalf("expected accelerator %q, got %q", config.DefaultAccelerator, cfg.Accelerator)
+	}
+	if !cfg.RESTServerEnabled() {
+		t.Fatalf("expected REST server enabled by default")
+	}
+}
+
+func TestLoaderRESTEnabledOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_REST_ENABLED": "false",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.RESTServerEnabled() {
+		t.Fatalf("expected REST server disabled when NUPI_REST_ENABLED=false")
+	}
+}
+
+func TestLoaderAcceleratorOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ACCELERATOR": "coreml",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, config.AcceleratorCoreML, cfg.Accelerator, "accelerator")
+}
+
+func TestLoaderDeviceIndexAndBLASLibraryOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ACCELERATOR":        "blas",
+		"WHISPERCPP_DEVICE_INDEX": "0",
+		"WHISPERCPP_BLAS_LIBRARY": "/usr/lib/libopenblas.so",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.DeviceIndex == nil || *cfg.DeviceIndex != 0 {
+		t.Fatalf("expected device index 0, got %v", cfg.DeviceIndex)
+	}
+	assertEqual(t, "/usr/lib/libopenblas.so", cfg.BLASLibrary, "blas_library")
+}
+
+func TestLoaderWordTimestampsOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_WORD_TIMESTAMPS": "true",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertBool(t, true, cfg.WordTimestamps, "word timestamps")
+}
+
+func TestLoaderDiarizationOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_DIARIZATION": "true",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertBool(t, true, cfg.Diarization, "diarization")
+}
+
+func TestLoaderStreamStabilizeOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_STREAM_STABILIZE": "true",
+		"NUPI_STABLE_THRESHOLD": "4",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertBool(t, true, cfg.StreamStabilize, "stream stabilize")
+	if cfg.StableThreshold != 4 {
+		t.Fatalf("expected stable threshold 4, got %d", cfg.StableThreshold)
+	}
+}
+
+func TestLoaderVADOverrides(t *testing.T) {
+	env := map[string]string{
+		"NUPI_VAD_MODE":                 "energy",
+		"NUPI_VAD_MIN_SILENCE_MS":       "750",
+		"NUPI_VAD_MAX_UTTERANCE_MS":     "20000",
+		"NUPI_VAD_PROMPT_CONTEXT_CHARS": "400",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	assertEqual(t, config.VADModeEnergy, cfg.VADMode, "vad mode")
+	if cfg.MinSilenceMs != 750 {
+		t.Fatalf("expected min silence ms 750, got %d", cfg.MinSilenceMs)
+	}
+	if cfg.MaxUtteranceMs != 20000 {
+		t.Fatalf("expected max utterance ms 20000, got %d", cfg.MaxUtteranceMs)
+	}
+	if cfg.PromptContextChars != 400 {
+		t.Fatalf("expected prompt context chars 400, got %d", cfg.PromptContextChars)
+	}
+}
+
+func TestLoaderVADGateOverrides(t *testing.T) {
+	env := map[string]string{
+		"NUPI_VAD_GATE_BACKEND":        "energy",
+		"NUPI_VAD_GATE_MIN_SPEECH_MS":  "150",
+		"NUPI_VAD_GATE_MIN_SILENCE_MS": "400",
+		"NUPI_VAD_GATE_PRE_ROLL_MS":    "100",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	assertEqual(t, config.VADGateBackendEnergy, cfg.VADGateBackend, "vad gate backend")
+	if cfg.VADGateMinSpeechMs != 150 {
+		t.Fatalf("expected vad gate min speech ms 150, got %d", cfg.VADGateMinSpeechMs)
+	}
+	if cfg.VADGateMinSilenceMs != 400 {
+		t.Fatalf("expected vad gate min silence ms 400, got %d", cfg.VADGateMinSilenceMs)
+	}
+	if cfg.VADGatePreRollMs != 100 {
+		t.Fatalf("expected vad gate pre roll ms 100, got %d", cfg.VADGatePreRollMs)
+	}
 }
 
 func TestLoaderOverrides(t *testing.T) {
@@ -83,6 +296,53 @@ func TestLoaderOverrides(t *testing.T) {
 	assertIntPtr(t, 6, cfg.Threads, "threads")
 }
 
+func TestLoaderRemoteBackend(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_BACKEND": "grpc://127.0.0.1:9000",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if !cfg.IsRemoteBackend() {
+		t.Fatalf("expected remote backend for %q", cfg.Backend)
+	}
+	assertEqual(t, "127.0.0.1:9000", cfg.BackendAddress(), "backend address")
+}
+
+func TestLoaderSupervisedBackend(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_BACKEND": "subprocess://whisper-worker",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.IsRemoteBackend() {
+		t.Fatalf("expected subprocess backend to not be remote")
+	}
+	if !cfg.IsSupervisedBackend() {
+		t.Fatalf("expected supervised backend for %q", cfg.Backend)
+	}
+	assertEqual(t, "whisper-worker", cfg.SupervisedBinary(), "supervised binary")
+}
+
 func TestLoaderThreadsAuto(t *testing.T) {
 	env := map[string]string{
 		"NUPI_ADAPTER_CONFIG": `{"threads":0}`,
@@ -105,6 +365,187 @@ func TestLoaderThreadsAuto(t *testing.T) {
 	}
 }
 
+func TestLoaderDefaultsMetricsAddr(t *testing.T) {
+	loader := config.Loader{}
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, config.DefaultMetricsAddr, cfg.MetricsAddr, "metrics addr")
+	if cfg.OTLPEndpoint != "" {
+		t.Fatalf("expected empty otlp endpoint, got %q", cfg.OTLPEndpoint)
+	}
+}
+
+func TestLoaderDefaultsRESTAddr(t *testing.T) {
+	loader := config.Loader{}
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, config.DefaultRESTAddr, cfg.RESTAddr, "rest addr")
+}
+
+func TestLoaderRESTAddrOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_REST_ADDR": "0.0.0.0:9465",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, "0.0.0.0:9465", cfg.RESTAddr, "rest addr")
+}
+
+func TestLoaderDefaultsWebRTCAddr(t *testing.T) {
+	loader := config.Loader{}
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, config.DefaultWebRTCAddr, cfg.WebRTCAddr, "webrtc addr")
+}
+
+func TestLoaderWebRTCAddrOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_WEBRTC_ADDR": "0.0.0.0:9466",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, "0.0.0.0:9466", cfg.WebRTCAddr, "webrtc addr")
+}
+
+func TestLoaderTelemetryOverrides(t *testing.T) {
+	env := map[string]string{
+		"NUPI_OTEL_EXPORTER_OTLP_ENDPOINT":    "http://otel-collector:4318",
+		"NUPI_OTEL_EXPORTER_OTLP_HEADERS":     "Authorization=Bearer secret, X-Scope-OrgID=1",
+		"NUPI_OTEL_EXPORTER_OTLP_INSECURE":    "true",
+		"NUPI_OTEL_EXPORTER_OTLP_COMPRESSION": "gzip",
+		"NUPI_METRICS_ADDR":                   "0.0.0.0:9464",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, "http://otel-collector:4318", cfg.OTLPEndpoint, "otlp endpoint")
+	assertEqual(t, "0.0.0.0:9464", cfg.MetricsAddr, "metrics addr")
+	assertEqual(t, "Bearer secret", cfg.OTLPHeaders["Authorization"], "otlp header Authorization")
+	assertEqual(t, "1", cfg.OTLPHeaders["X-Scope-OrgID"], "otlp header X-Scope-OrgID")
+	if !cfg.OTLPInsecure {
+		t.Fatalf("expected otlp insecure to be true")
+	}
+	assertEqual(t, "gzip", cfg.OTLPCompression, "otlp compression")
+}
+
+func TestLoaderDefaultsOTLPCompression(t *testing.T) {
+	loader := config.Loader{}
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	assertEqual(t, config.DefaultOTLPCompression, cfg.OTLPCompression, "otlp compression")
+}
+
+func TestLoaderModelsOverride(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_MODELS": `[
+			{"variant": "tiny.en", "languages": ["en"]},
+			{"variant": "large-v3", "languages": ["pl", "de"], "use_gpu": true}
+		]`,
+		"NUPI_ADAPTER_MAX_RESIDENT_MODELS": "2",
+	}
+
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(cfg.Models) != 2 {
+		t.Fatalf("unexpected models count: %d", len(cfg.Models))
+	}
+	assertEqual(t, "tiny.en", cfg.Models[0].Variant, "models[0].variant")
+	assertEqual(t, "large-v3", cfg.Models[1].Variant, "models[1].variant")
+	if cfg.Models[1].UseGPU == nil || !*cfg.Models[1].UseGPU {
+		t.Fatalf("expected models[1].use_gpu true")
+	}
+	if cfg.MaxResidentModels != 2 {
+		t.Fatalf("unexpected max resident models: %d", cfg.MaxResidentModels)
+	}
+}
+
+func TestLoaderMaxResidentBytesOverride(t *testing.T) {
+	env := map[string]string{"NUPI_ADAPTER_MAX_RESIDENT_BYTES": "1073741824"}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+
+	cfg, err := loader.Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.MaxResidentBytes != 1073741824 {
+		t.Fatalf("unexpected max resident bytes: %d", cfg.MaxResidentBytes)
+	}
+}
+
+func TestLoaderModelsInvalidJSON(t *testing.T) {
+	env := map[string]string{"NUPI_ADAPTER_MODELS": "not json"}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	if _, err := loader.Load(); err == nil {
+		t.Fatalf("expected error for invalid NUPI_ADAPTER_MODELS JSON")
+	}
+}
+
+func TestLoaderDefaultMaxResidentModels(t *testing.T) {
+	cfg, err := (config.Loader{}).Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.MaxResidentModels != config.DefaultMaxResidentModels {
+		t.Fatalf("unexpected max resident models: %d", cfg.MaxResidentModels)
+	}
+}
+
 func assertEqual(t *testing.T, want, got, label string) {
 	t.Helper()
 	if want != got {
@@ -138,3 +579,168 @@ func assertIntPtr(t *testing.T, want int, got *int, label string) {
 		t.Fatalf("unexpected %s: want %d, got %d", label, want, *got)
 	}
 }
+
+func TestLoaderLoadAllSingleObject(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `{"model_variant": "base"}`,
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	configs, err := loader.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() returned error: %v", err)
+	}
+	if len(configs) != 1 {
+		t.Fatalf("unexpected instance count: %d", len(configs))
+	}
+	assertEqual(t, "base", configs[0].ModelVariant, "configs[0].model_variant")
+	assertEqual(t, "", configs[0].Alias, "configs[0].alias")
+}
+
+func TestLoaderLoadAllArray(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[
+			{"alias": "en", "listen_addr": "127.0.0.1:50061", "rest_addr": "127.0.0.1:9466", "model_variant": "tiny.en", "language": "en"},
+			{"alias": "pl", "listen_addr": "127.0.0.1:50062", "rest_addr": "127.0.0.1:9467", "model_variant": "large-v3", "language": "pl", "use_gpu": true}
+		]`,
+		"NUPI_LOG_LEVEL": "debug",
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	configs, err := loader.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() returned error: %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("unexpected instance count: %d", len(configs))
+	}
+	assertEqual(t, "en", configs[0].Alias, "configs[0].alias")
+	assertEqual(t, "127.0.0.1:50061", configs[0].ListenAddr, "configs[0].listen_addr")
+	assertEqual(t, "tiny.en", configs[0].ModelVariant, "configs[0].model_variant")
+	assertEqual(t, "debug", configs[0].LogLevel, "configs[0].log_level")
+	assertEqual(t, "pl", configs[1].Alias, "configs[1].alias")
+	assertEqual(t, "127.0.0.1:50062", configs[1].ListenAddr, "configs[1].listen_addr")
+	if configs[1].UseGPU == nil || !*configs[1].UseGPU {
+		t.Fatalf("expected configs[1].use_gpu true")
+	}
+}
+
+func TestLoaderLoadAllRequiresAlias(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[{"listen_addr": "127.0.0.1:50061"}]`,
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	if _, err := loader.LoadAll(); err == nil {
+		t.Fatalf("expected error for instance with no alias")
+	}
+}
+
+func TestLoaderLoadAllRejectsDuplicateAlias(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[
+			{"alias": "en", "listen_addr": "127.0.0.1:50061"},
+			{"alias": "en", "listen_addr": "127.0.0.1:50062"}
+		]`,
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	if _, err := loader.LoadAll(); err == nil {
+		t.Fatalf("expected error for duplicate alias")
+	}
+}
+
+func TestLoaderLoadAllRejectsDuplicateListenAddr(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[
+			{"alias": "en", "listen_addr": "127.0.0.1:50061", "rest_addr": "127.0.0.1:9466"},
+			{"alias": "pl", "listen_addr": "127.0.0.1:50061", "rest_addr": "127.0.0.1:9467"}
+		]`,
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	if _, err := loader.LoadAll(); err == nil {
+		t.Fatalf("expected error for duplicate listen_addr")
+	}
+}
+
+func TestLoaderLoadAllDefaultWebRTCAddrDoesNotCollide(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[
+			{"alias": "en", "listen_addr": "127.0.0.1:50061", "rest_addr": "127.0.0.1:9466"},
+			{"alias": "pl", "listen_addr": "127.0.0.1:50062", "rest_addr": "127.0.0.1:9467"}
+		]`,
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	configs, err := loader.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() returned error: %v", err)
+	}
+	assertEqual(t, config.DefaultWebRTCAddr, configs[0].WebRTCAddr, "configs[0].webrtc_addr")
+	assertEqual(t, config.DefaultWebRTCAddr, configs[1].WebRTCAddr, "configs[1].webrtc_addr")
+}
+
+func TestLoaderLoadAllRejectsDuplicateExplicitWebRTCAddr(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[
+			{"alias": "en", "listen_addr": "127.0.0.1:50061", "rest_addr": "127.0.0.1:9466", "webrtc_addr": "127.0.0.1:9470"},
+			{"alias": "pl", "listen_addr": "127.0.0.1:50062", "rest_addr": "127.0.0.1:9467", "webrtc_addr": "127.0.0.1:9470"}
+		]`,
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	if _, err := loader.LoadAll(); err == nil {
+		t.Fatalf("expected error for duplicate explicit webrtc_addr")
+	}
+}
+
+func TestLoaderLoadAllDataDirIsShared(t *testing.T) {
+	env := map[string]string{
+		"NUPI_ADAPTER_CONFIG": `[
+			{"alias": "en", "listen_addr": "127.0.0.1:50061", "rest_addr": "127.0.0.1:9466", "data_dir": "/data/en"},
+			{"alias": "pl", "listen_addr": "127.0.0.1:50062", "rest_addr": "127.0.0.1:9467", "data_dir": "/data/pl"}
+		]`,
+		"NUPI_ADAPTER_DATA_DIR": "/data/shared",
+	}
+	loader := config.Loader{
+		Lookup: func(key string) (string, bool) {
+			value, ok := env[key]
+			return value, ok
+		},
+	}
+	configs, err := loader.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() returned error: %v", err)
+	}
+	assertEqual(t, "/data/shared", configs[0].DataDir, "configs[0].data_dir")
+	assertEqual(t, "/data/shared", configs[1].DataDir, "configs[1].data_dir")
+}
@@ -0,0 +1,25 @@
+package engine
+
+// Capabilities describes which native accelerator backends were compiled
+// into this binary. NativeAvailable reports it so callers (engine.New,
+// diagnostics endpoints) can negotiate an accelerator instead of treating
+// "native" as a single on/off switch.
+type Capabilities struct {
+	// Native reports whether the whispercpp build tag was compiled in at all.
+	Native bool
+	// Metal reports Apple Metal GPU support (darwin only).
+	Metal bool
+	// CUDA reports NVIDIA CUDA GPU support.
+	CUDA bool
+	// CoreML reports support for loading a Core ML sidecar alongside the
+	// ggml model.
+	CoreML bool
+	// OpenVINO reports support for loading an OpenVINO IR sidecar alongside
+	// the ggml model.
+	OpenVINO bool
+	// BLAS reports whether a BLAS backend (OpenBLAS, or the platform's
+	// vendor cBLAS) was linked in for CPU inference.
+	BLAS bool
+	// Vulkan reports Vulkan compute backend support.
+	Vulkan bool
+}
@@ -19,6 +19,38 @@ type Options struct {
 	Final bool
 	// Sequence carries the original sequence number from the segment, when available.
 	Sequence uint64
+	// Prompt, when set, is fed to the decoder as context bias (whisper's
+	// initial_prompt / prompt_tokens), typically the previous utterance's
+	// final transcript so continuity carries across utterance boundaries.
+	Prompt string
+	// UtteranceOffsetMs is the stream-absolute timestamp, in milliseconds,
+	// at which this segment's audio begins. Engines that produce per-word
+	// timestamps add it to whisper's utterance-relative timings so
+	// Result.Words carry stream-absolute times.
+	UtteranceOffsetMs uint64
+	// Variant, when set, names the model variant that should handle this
+	// call explicitly (e.g. a stream's "variant" metadata), overriding
+	// language-based routing. Only EnginePool interprets it; other Engine
+	// implementations ignore it.
+	Variant string
+	// SessionID identifies the caller's stream (a gRPC StreamTranscription
+	// session_id, a REST upload, ...) so an engine that keeps per-stream
+	// decoding state (rolling buffer, keep_ms history, language config, last
+	// confidence) can key off it instead of serialising every concurrent
+	// caller onto one shared state. Only NativeEngine interprets it; other
+	// Engine implementations ignore it, and an empty SessionID is treated as
+	// a single default session.
+	SessionID string
+	// OutputFormat asks the caller-facing surface (gRPC, REST) to render
+	// this call's final transcript as "text", "json", "srt", "vtt", or
+	// "csv" instead of plain text; see internal/format. Engines themselves
+	// ignore it and always return plain Result.Text. Empty means "text".
+	OutputFormat string
+	// Translate asks the engine to translate this call's audio to English
+	// instead of transcribing it in its source language, mirroring
+	// whisper.cpp's --translate. Only NativeEngine interprets it; other
+	// Engine implementations ignore it.
+	Translate bool
 }
 
 // Result represents a transcript produced by the engine.
@@ -26,4 +58,43 @@ type Result struct {
 	Text       string
 	Confidence float32
 	Final      bool
+	// Words carries per-word timing, populated when the engine was
+	// configured with WordTimestamps enabled. Empty otherwise.
+	Words []Word
+	// AvgLogprob is whisper's average log probability across the segment's
+	// tokens; lower (more negative) indicates lower decoder confidence.
+	AvgLogprob float32
+	// NoSpeechProb is whisper's estimated probability that the segment
+	// contains no speech at all.
+	NoSpeechProb float32
+	// CompressionRatio is the gzip compression ratio of the segment text;
+	// unusually high values often indicate repetition artefacts.
+	CompressionRatio float32
+	// StartMs and EndMs bound this segment's audio, stream-absolute, in
+	// milliseconds. Zero when the engine does not report segment-level
+	// timing (e.g. StubEngine).
+	StartMs uint64
+	EndMs   uint64
+	// StableOffset is the cumulative number of words of this stream's
+	// transcript confirmed stable so far, populated when StabilizedEngine
+	// wraps the inner engine (config.Config.StreamStabilize). When Final is
+	// true, Text is the newly-confirmed words ending at StableOffset; when
+	// Final is false, Text is the still-unstable tail starting at
+	// StableOffset and may be replaced wholesale by a later result with the
+	// same StableOffset. Zero when stabilization is not in effect.
+	StableOffset int
+}
+
+// Word is a single word-level timing produced by whisper's DTW token
+// alignment.
+type Word struct {
+	Text        string
+	StartMs     uint64
+	EndMs       uint64
+	Probability float32
+	// SpeakerTurn reports whether whisper.cpp's TinyDiarize mode detected a
+	// speaker change immediately after this word. Always false unless the
+	// engine was configured with NativeOptions.TinyDiarize; TinyDiarize only
+	// marks turn boundaries, not speaker identity.
+	SpeakerTurn bool
 }
@@ -0,0 +1,35 @@
+package enginerpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codecName is registered as a gRPC wire codec so EngineService can be
+// served and dialed without a protobuf-generated message implementation.
+const codecName = "enginerpc-json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return codecName }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("enginerpc: marshal %T: %w", v, err)
+	}
+	return data, nil
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("enginerpc: unmarshal %T: %w", v, err)
+	}
+	return nil
+}
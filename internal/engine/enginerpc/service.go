@@ -0,0 +1,171 @@
+package enginerpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ServiceName is the fully-qualified gRPC service name from
+// proto/engine/v1/engine.proto.
+const ServiceName = "nupi.stt.engine.v1.EngineService"
+
+// EngineServiceServer is implemented by whatever hosts an engine.Engine for
+// remote callers (see engine.NewGRPCEngine for the client side).
+type EngineServiceServer interface {
+	TranscribeSegment(context.Context, *TranscribeSegmentRequest) (*TranscribeSegmentResponse, error)
+	Flush(context.Context, *FlushRequest) (*FlushResponse, error)
+	SetDefaultLanguage(context.Context, *SetDefaultLanguageRequest) (*Empty, error)
+	Close(context.Context, *Empty) (*Empty, error)
+	GetCapabilities(context.Context, *Empty) (*CapabilitiesResponse, error)
+}
+
+// RegisterEngineServiceServer wires srv into a *grpc.Server under
+// ServiceName.
+func RegisterEngineServiceServer(s *grpc.Server, srv EngineServiceServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*EngineServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TranscribeSegment",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := new(TranscribeSegmentRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(EngineServiceServer).TranscribeSegment(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ServiceName + "/TranscribeSegment"}
+				handler := func(ctx context.Context, req any) (any, error) {
+					return srv.(EngineServiceServer).TranscribeSegment(ctx, req.(*TranscribeSegmentRequest))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+		{
+			MethodName: "Flush",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := new(FlushRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(EngineServiceServer).Flush(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ServiceName + "/Flush"}
+				handler := func(ctx context.Context, req any) (any, error) {
+					return srv.(EngineServiceServer).Flush(ctx, req.(*FlushRequest))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+		{
+			MethodName: "SetDefaultLanguage",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := new(SetDefaultLanguageRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(EngineServiceServer).SetDefaultLanguage(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ServiceName + "/SetDefaultLanguage"}
+				handler := func(ctx context.Context, req any) (any, error) {
+					return srv.(EngineServiceServer).SetDefaultLanguage(ctx, req.(*SetDefaultLanguageRequest))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+		{
+			MethodName: "Close",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := new(Empty)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(EngineServiceServer).Close(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ServiceName + "/Close"}
+				handler := func(ctx context.Context, req any) (any, error) {
+					return srv.(EngineServiceServer).Close(ctx, req.(*Empty))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				req := new(Empty)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(EngineServiceServer).GetCapabilities(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: ServiceName + "/GetCapabilities"}
+				handler := func(ctx context.Context, req any) (any, error) {
+					return srv.(EngineServiceServer).GetCapabilities(ctx, req.(*Empty))
+				}
+				return interceptor(ctx, req, info, handler)
+			},
+		},
+	},
+	Metadata: "proto/engine/v1/engine.proto",
+}
+
+// Client calls a remote EngineService, using the enginerpc-json codec so no
+// protobuf-generated message types are required on either end.
+type Client struct {
+	cc *grpc.ClientConn
+}
+
+// NewClient wraps an established connection.
+func NewClient(cc *grpc.ClientConn) *Client {
+	return &Client{cc: cc}
+}
+
+func (c *Client) TranscribeSegment(ctx context.Context, req *TranscribeSegmentRequest) (*TranscribeSegmentResponse, error) {
+	resp := new(TranscribeSegmentResponse)
+	if err := c.cc.Invoke(ctx, "/"+ServiceName+"/TranscribeSegment", req, resp, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Flush(ctx context.Context, req *FlushRequest) (*FlushResponse, error) {
+	resp := new(FlushResponse)
+	if err := c.cc.Invoke(ctx, "/"+ServiceName+"/Flush", req, resp, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) SetDefaultLanguage(ctx context.Context, req *SetDefaultLanguageRequest) (*Empty, error) {
+	resp := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+ServiceName+"/SetDefaultLanguage", req, resp, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) Close(ctx context.Context, req *Empty) (*Empty, error) {
+	resp := new(Empty)
+	if err := c.cc.Invoke(ctx, "/"+ServiceName+"/Close", req, resp, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *Client) GetCapabilities(ctx context.Context, req *Empty) (*CapabilitiesResponse, error) {
+	resp := new(CapabilitiesResponse)
+	if err := c.cc.Invoke(ctx, "/"+ServiceName+"/GetCapabilities", req, resp, grpc.CallContentSubtype(codecName)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,83 @@
+// Package enginerpc implements the wire contract described by
+// proto/engine/v1/engine.proto by hand, since this repository does not yet
+// vendor a protoc toolchain of its own (shared Nupi platform contracts such
+// as napv1 are generated in the sibling nupi repository). The message
+// shapes below are kept in lockstep with the .proto file; once this
+// repository grows real codegen, this package can be replaced with the
+// generated equivalent without changing callers.
+package enginerpc
+
+// Options mirrors engine.Options.
+type Options struct {
+	Language          string `json:"language,omitempty"`
+	Final             bool   `json:"final,omitempty"`
+	Sequence          uint64 `json:"sequence,omitempty"`
+	Prompt            string `json:"prompt,omitempty"`
+	UtteranceOffsetMs uint64 `json:"utterance_offset_ms,omitempty"`
+	Variant           string `json:"variant,omitempty"`
+	SessionID         string `json:"session_id,omitempty"`
+	OutputFormat      string `json:"output_format,omitempty"`
+}
+
+// Result mirrors engine.Result.
+type Result struct {
+	Text             string  `json:"text,omitempty"`
+	Confidence       float32 `json:"confidence,omitempty"`
+	Final            bool    `json:"final,omitempty"`
+	Words            []Word  `json:"words,omitempty"`
+	AvgLogprob       float32 `json:"avg_logprob,omitempty"`
+	NoSpeechProb     float32 `json:"no_speech_prob,omitempty"`
+	CompressionRatio float32 `json:"compression_ratio,omitempty"`
+	StartMs          uint64  `json:"start_ms,omitempty"`
+	EndMs            uint64  `json:"end_ms,omitempty"`
+}
+
+// Word mirrors engine.Word.
+type Word struct {
+	Text        string  `json:"text,omitempty"`
+	StartMs     uint64  `json:"start_ms,omitempty"`
+	EndMs       uint64  `json:"end_ms,omitempty"`
+	Probability float32 `json:"probability,omitempty"`
+	SpeakerTurn bool    `json:"speaker_turn,omitempty"`
+}
+
+// TranscribeSegmentRequest is the payload for EngineService.TranscribeSegment.
+type TranscribeSegmentRequest struct {
+	Audio   []byte  `json:"audio,omitempty"`
+	Options Options `json:"options"`
+}
+
+// TranscribeSegmentResponse is the reply for EngineService.TranscribeSegment.
+type TranscribeSegmentResponse struct {
+	Results []Result `json:"results,omitempty"`
+}
+
+// FlushRequest is the payload for EngineService.Flush.
+type FlushRequest struct {
+	Options Options `json:"options"`
+}
+
+// FlushResponse is the reply for EngineService.Flush.
+type FlushResponse struct {
+	Results []Result `json:"results,omitempty"`
+}
+
+// SetDefaultLanguageRequest is the payload for EngineService.SetDefaultLanguage.
+type SetDefaultLanguageRequest struct {
+	Language string `json:"language,omitempty"`
+}
+
+// CapabilitiesResponse mirrors engine.Capabilities, reported by
+// EngineService.GetCapabilities.
+type CapabilitiesResponse struct {
+	Native   bool `json:"native,omitempty"`
+	Metal    bool `json:"metal,omitempty"`
+	CUDA     bool `json:"cuda,omitempty"`
+	CoreML   bool `json:"core_ml,omitempty"`
+	OpenVINO bool `json:"open_vino,omitempty"`
+	BLAS     bool `json:"blas,omitempty"`
+	Vulkan   bool `json:"vulkan,omitempty"`
+}
+
+// Empty is the shared empty message used by EngineService.
+type Empty struct{}
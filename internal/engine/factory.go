@@ -3,12 +3,15 @@ package engine
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 
 	"log/slog"
 
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/segmenter"
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/models"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
 )
 
 // ErrNativeEngineUnavailable indicates that a real native backend is not yet wired in.
@@ -16,20 +19,33 @@ var ErrNativeEngineUnavailable = errors.New("engine: native backend unavailable"
 
 // New resolves the desired model and returns an Engine instance.
 // Currently the implementation falls back to the stub engine when the native backend
-// is unavailable or model artefacts cannot be ensured locally.
-func New(cfg config.Config, manager *models.Manager, logger *slog.Logger) (Engine, string, error) {
+// is unavailable or model artefacts cannot be ensured locally. metrics, when
+// non-nil, is handed to an EnginePool (see cfg.Models) so pool hit/miss
+// counters are recorded; it is unused by every other backend.
+func New(cfg config.Config, manager *models.Manager, logger *slog.Logger, metrics *telemetry.Recorder) (Engine, string, error) {
 	manifest, err := models.DefaultManifest()
+	var (
+		eng      Engine
+		path     string
+		buildErr error
+	)
 	if err != nil {
-		return newEngineWithOptions(cfg, manager, logger, engineOptions{})
+		eng, path, buildErr = newEngineWithOptions(cfg, manager, logger, metrics, engineOptions{})
+	} else {
+		eng, path, buildErr = newEngineWithOptions(cfg, manager, logger, metrics, engineOptions{
+			manifest: manifest,
+			ensure: models.EnsureOptions{
+				Manifest:    manifest,
+				Override:    cfg.ModelPath,
+				Accelerator: cfg.Accelerator,
+			},
+		})
 	}
 
-	return newEngineWithOptions(cfg, manager, logger, engineOptions{
-		manifest: manifest,
-		ensure: models.EnsureOptions{
-			Manifest: manifest,
-			Override: cfg.ModelPath,
-		},
-	})
+	if cfg.StreamStabilize && eng != nil {
+		eng = NewStabilizedEngine(eng, cfg.StableThreshold)
+	}
+	return eng, path, buildErr
 }
 
 type engineOptions struct {
@@ -37,11 +53,31 @@ type engineOptions struct {
 	ensure   models.EnsureOptions
 }
 
-func newEngineWithOptions(cfg config.Config, manager *models.Manager, logger *slog.Logger, opts engineOptions) (Engine, string, error) {
+func newEngineWithOptions(cfg config.Config, manager *models.Manager, logger *slog.Logger, metrics *telemetry.Recorder, opts engineOptions) (Engine, string, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
+	if cfg.IsRemoteBackend() {
+		remote, err := NewGRPCEngine(cfg.BackendAddress())
+		if err != nil {
+			logger.Error("remote engine dial failed; using stub engine", "error", err, "backend", cfg.Backend)
+			return NewStubEngine(logger, cfg.ModelVariant), "", err
+		}
+		logger.Info("remote gRPC engine ready", "backend", cfg.Backend)
+		return remote, "", nil
+	}
+
+	if cfg.IsSupervisedBackend() {
+		supervised, err := NewSupervisedEngine(cfg.SupervisedBinary(), supervisedBackendOptions(cfg), logger)
+		if err != nil {
+			logger.Error("subprocess engine spawn failed; using stub engine", "error", err, "backend", cfg.Backend)
+			return NewStubEngine(logger, cfg.ModelVariant), "", err
+		}
+		logger.Info("supervised subprocess engine ready", "backend", cfg.Backend)
+		return supervised, "", nil
+	}
+
 	if cfg.UseStubEngine {
 		path := ""
 		if manager != nil && strings.TrimSpace(cfg.ModelPath) != "" {
@@ -60,6 +96,16 @@ func newEngineWithOptions(cfg config.Config, manager *models.Manager, logger *sl
 		return NewStubEngine(logger, cfg.ModelVariant), "", ErrNativeEngineUnavailable
 	}
 
+	if len(cfg.Models) > 0 {
+		pool, err := NewEnginePool(cfg, manager, logger, metrics)
+		if err != nil {
+			logger.Warn("engine pool initialisation failed; using stub engine", "error", err)
+			return NewStubEngine(logger, cfg.ModelVariant), "", err
+		}
+		logger.Info("engine pool ready", "variants", pool.Variants(), "max_resident", cfg.MaxResidentModels)
+		return pool, "", nil
+	}
+
 	if opts.ensure.Manifest.Variants == nil || len(opts.ensure.Manifest.Variants) == 0 {
 		return NewStubEngine(logger, cfg.ModelVariant), "", errors.New("models: manifest is empty")
 	}
@@ -70,16 +116,72 @@ func newEngineWithOptions(cfg config.Config, manager *models.Manager, logger *sl
 		return NewStubEngine(logger, cfg.ModelVariant), "", err
 	}
 
-	if NativeAvailable() {
-		native, nativeErr := NewNativeEngine(modelPath)
+	if NativeAvailable().Native {
+		native, nativeErr := NewNativeEngine(modelPath, NativeOptions{
+			UseGPU:         cfg.UseGPU,
+			FlashAttention: cfg.FlashAttention,
+			Threads:        cfg.Threads,
+			Accelerator:    cfg.Accelerator,
+			DeviceIndex:    cfg.DeviceIndex,
+			BLASLibrary:    cfg.BLASLibrary,
+			WordTimestamps: &cfg.WordTimestamps,
+			TinyDiarize:    &cfg.Diarization,
+		})
 		if nativeErr != nil {
 			logger.Error("native engine initialisation failed; using stub", "error", nativeErr, "model_path", modelPath)
 			return NewStubEngine(logger, cfg.ModelVariant), modelPath, nativeErr
 		}
 		logger.Info("native engine ready", "model_path", modelPath)
+		if cfg.VADMode == config.VADModeEnergy {
+			logger.Info("wrapping native engine with VAD-driven segmentation",
+				"min_silence_ms", cfg.MinSilenceMs, "max_utterance_ms", cfg.MaxUtteranceMs)
+			return NewSegmentedEngine(native, segmenter.Config{
+				MinSilenceMs:   cfg.MinSilenceMs,
+				MaxUtteranceMs: cfg.MaxUtteranceMs,
+			}, cfg.PromptContextChars), modelPath, nil
+		}
 		return native, modelPath, nil
 	}
 
 	logger.Warn("native backend disabled at build time; using stub engine", "model_path", modelPath)
 	return NewStubEngine(logger, cfg.ModelVariant), modelPath, ErrNativeEngineUnavailable
 }
+
+// supervisedBackendOptions projects the subset of cfg that configures how a
+// subprocess backend should decode into the environment variable
+// assignments SupervisedEngine forwards to the child it spawns. Each key is
+// the same env var the child's own config.Loader already understands, so
+// passing backend-specific flags through a generic map here doesn't require
+// either side to agree on a new wire format.
+func supervisedBackendOptions(cfg config.Config) map[string]string {
+	options := map[string]string{
+		"NUPI_MODEL_VARIANT":   cfg.ModelVariant,
+		"NUPI_WORD_TIMESTAMPS": strconv.FormatBool(cfg.WordTimestamps),
+		"NUPI_DIARIZATION":     strconv.FormatBool(cfg.Diarization),
+	}
+	if cfg.Language != "" {
+		options["NUPI_LANGUAGE_HINT"] = cfg.Language
+	}
+	if cfg.ModelPath != "" {
+		options["NUPI_MODEL_PATH"] = cfg.ModelPath
+	}
+	if cfg.Accelerator != "" {
+		options["NUPI_ACCELERATOR"] = cfg.Accelerator
+	}
+	if cfg.BLASLibrary != "" {
+		options["WHISPERCPP_BLAS_LIBRARY"] = cfg.BLASLibrary
+	}
+	if cfg.DeviceIndex != nil {
+		options["WHISPERCPP_DEVICE_INDEX"] = strconv.Itoa(*cfg.DeviceIndex)
+	}
+	if cfg.UseGPU != nil {
+		options["WHISPERCPP_USE_GPU"] = strconv.FormatBool(*cfg.UseGPU)
+	}
+	if cfg.FlashAttention != nil {
+		options["WHISPERCPP_FLASH_ATTENTION"] = strconv.FormatBool(*cfg.FlashAttention)
+	}
+	if cfg.Threads != nil {
+		options["WHISPERCPP_THREADS"] = strconv.Itoa(*cfg.Threads)
+	}
+	return options
+}
@@ -14,7 +14,7 @@ import (
 
 func TestNewUsesStubWhenForced(t *testing.T) {
 	cfg := config.Config{ModelVariant: "base", UseStubEngine: true}
-	engine, modelPath, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	engine, modelPath, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
 	if err != nil {
 		t.Fatalf("expected nil error, got %v", err)
 	}
@@ -26,6 +26,77 @@ func TestNewUsesStubWhenForced(t *testing.T) {
 	}
 }
 
+func TestNewUsesRemoteGRPCBackend(t *testing.T) {
+	cfg := config.Config{ModelVariant: "base", Backend: "grpc://127.0.0.1:0"}
+	eng, modelPath, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	if err != nil {
+		t.Fatalf("expected nil error dialing lazily, got %v", err)
+	}
+	if modelPath != "" {
+		t.Fatalf("expected empty model path for remote backend, got %q", modelPath)
+	}
+	remote, ok := eng.(*GRPCEngine)
+	if !ok {
+		t.Fatalf("expected *GRPCEngine, got %T", eng)
+	}
+	if err := remote.Close(); err != nil {
+		t.Logf("Close() returned %v (expected: nothing listens on 127.0.0.1:0)", err)
+	}
+}
+
+func TestNewWrapsEngineWithStabilizerWhenEnabled(t *testing.T) {
+	cfg := config.Config{ModelVariant: "base", UseStubEngine: true, StreamStabilize: true, StableThreshold: 3}
+	eng, _, err := New(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	stabilized, ok := eng.(*StabilizedEngine)
+	if !ok {
+		t.Fatalf("expected *StabilizedEngine, got %T", eng)
+	}
+	if stabilized.threshold != 3 {
+		t.Fatalf("expected threshold 3, got %d", stabilized.threshold)
+	}
+	if _, ok := stabilized.inner.(*StubEngine); !ok {
+		t.Fatalf("expected inner engine to be *StubEngine, got %T", stabilized.inner)
+	}
+}
+
+func TestNewWrapsNativeEngineWithSegmenter(t *testing.T) {
+	tempDir := t.TempDir()
+	manager, err := models.NewManager(tempDir, nil)
+	if err != nil {
+		t.Fatalf("NewManager error: %v", err)
+	}
+
+	path := manager.ModelsDir() + "/ggml-base.en.bin"
+	if err := os.WriteFile(path, []byte("GGUF"), 0o644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	cfg := config.Config{ModelVariant: "base", VADMode: config.VADModeEnergy, MinSilenceMs: 500, MaxUtteranceMs: 15000}
+	manifest := models.Manifest{Variants: map[string]models.Variant{
+		"base": {DisplayName: "Base", Filename: "ggml-base.en.bin", URL: ""},
+	}}
+	if !NativeAvailable().Native {
+		t.Skip("native backend not compiled in")
+	}
+
+	eng, _, err := newEngineWithOptions(cfg, manager, nil, nil, engineOptions{
+		manifest: manifest,
+		ensure: models.EnsureOptions{
+			Manifest: manifest,
+			Override: cfg.ModelPath,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected native engine initialisation, got %v", err)
+	}
+	if _, ok := eng.(*SegmentedEngine); !ok {
+		t.Fatalf("expected *SegmentedEngine when VADMode is energy, got %T", eng)
+	}
+}
+
 func TestNewFallsBackWhenModelMissing(t *testing.T) {
 	tempDir := t.TempDir()
 	manager, err := models.NewManager(tempDir, nil)
@@ -39,7 +110,7 @@ func TestNewFallsBackWhenModelMissing(t *testing.T) {
 		UseStubEngine: true,
 	}
 	emptyManifest := models.Manifest{Variants: map[string]models.Variant{}}
-	engine, modelPath, err := newEngineWithOptions(cfg, manager, nil, engineOptions{
+	engine, modelPath, err := newEngineWithOptions(cfg, manager, nil, nil, engineOptions{
 		manifest: emptyManifest,
 		ensure: models.EnsureOptions{
 			Manifest: emptyManifest,
@@ -65,7 +136,7 @@ func TestNewResolvesModel(t *testing.T) {
 	}
 
 	path := manager.ModelsDir() + "/ggml-base.en.bin"
-	if err := os.WriteFile(path, []byte("stub"), 0o644); err != nil {
+	if err := os.WriteFile(path, []byte("GGUF"), 0o644); err != nil {
 		t.Fatalf("WriteFile error: %v", err)
 	}
 
@@ -77,24 +148,24 @@ func TestNewResolvesModel(t *testing.T) {
 			URL:         "",
 		},
 	}}
-	if NativeAvailable() {
+	if NativeAvailable().Native {
 		cfg.UseStubEngine = true
 	}
-	engine, modelPath, err := newEngineWithOptions(cfg, manager, nil, engineOptions{
+	engine, modelPath, err := newEngineWithOptions(cfg, manager, nil, nil, engineOptions{
 		manifest: manifest,
 		ensure: models.EnsureOptions{
 			Manifest: manifest,
 			Override: cfg.ModelPath,
 		},
 	})
-	if NativeAvailable() && cfg.UseStubEngine {
+	if NativeAvailable().Native && cfg.UseStubEngine {
 		if err != nil {
 			t.Fatalf("expected stub initialisation, got %v", err)
 		}
 		if modelPath != "" {
 			t.Fatalf("expected empty model path when stub forced, got %s", modelPath)
 		}
-	} else if NativeAvailable() {
+	} else if NativeAvailable().Native {
 		if err != nil {
 			t.Fatalf("expected native engine initialisation, got %v", err)
 		}
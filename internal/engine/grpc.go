@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/enginerpc"
+)
+
+// GRPCEngine implements Engine by delegating to an out-of-process engine
+// server reachable over gRPC. This isolates native C++ crashes (whisper.cpp,
+// a GPU OOM, a driver hang) from the adapter process, and lets one
+// GPU-resident model be shared by several adapter instances.
+type GRPCEngine struct {
+	conn   *grpc.ClientConn
+	client *enginerpc.Client
+}
+
+// NewGRPCEngine dials addr (host:port, no scheme) and returns an Engine that
+// forwards every call to the remote engine server.
+func NewGRPCEngine(addr string) (*GRPCEngine, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("engine: dial %s: %w", addr, err)
+	}
+	return &GRPCEngine{
+		conn:   conn,
+		client: enginerpc.NewClient(conn),
+	}, nil
+}
+
+func (e *GRPCEngine) TranscribeSegment(ctx context.Context, audio []byte, opts Options) ([]Result, error) {
+	resp, err := e.client.TranscribeSegment(ctx, &enginerpc.TranscribeSegmentRequest{
+		Audio:   audio,
+		Options: toRPCOptions(opts),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("engine: remote TranscribeSegment: %w", err)
+	}
+	return fromRPCResults(resp.Results), nil
+}
+
+func (e *GRPCEngine) Flush(ctx context.Context, opts Options) ([]Result, error) {
+	resp, err := e.client.Flush(ctx, &enginerpc.FlushRequest{Options: toRPCOptions(opts)})
+	if err != nil {
+		return nil, fmt.Errorf("engine: remote Flush: %w", err)
+	}
+	return fromRPCResults(resp.Results), nil
+}
+
+// SetDefaultLanguage configures the language hint used by the remote engine
+// when a request omits one.
+func (e *GRPCEngine) SetDefaultLanguage(lang string) {
+	_, _ = e.client.SetDefaultLanguage(context.Background(), &enginerpc.SetDefaultLanguageRequest{Language: lang})
+}
+
+// Healthy reports whether the remote engine server's grpc.health.v1 service
+// reports SERVING, so a caller like SupervisedEngine can confirm a freshly
+// spawned child is actually ready to decode before handing it traffic.
+// Workers too old to register a health service (or ones that don't) answer
+// Unimplemented, which is treated as healthy rather than failed.
+func (e *GRPCEngine) Healthy(ctx context.Context) bool {
+	resp, err := healthpb.NewHealthClient(e.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return isUnimplemented(err)
+	}
+	return resp.Status == healthpb.HealthCheckResponse_SERVING
+}
+
+func (e *GRPCEngine) Close() error {
+	_, err := e.client.Close(context.Background(), &enginerpc.Empty{})
+	if closeErr := e.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// GetCapabilities reports which accelerator backends the remote engine
+// server was compiled with.
+func (e *GRPCEngine) GetCapabilities(ctx context.Context) (Capabilities, error) {
+	resp, err := e.client.GetCapabilities(ctx, &enginerpc.Empty{})
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("engine: remote GetCapabilities: %w", err)
+	}
+	return Capabilities{
+		Native:   resp.Native,
+		Metal:    resp.Metal,
+		CUDA:     resp.CUDA,
+		CoreML:   resp.CoreML,
+		OpenVINO: resp.OpenVINO,
+		BLAS:     resp.BLAS,
+		Vulkan:   resp.Vulkan,
+	}, nil
+}
+
+func isUnimplemented(err error) bool {
+	return status.Code(err) == codes.Unimplemented
+}
+
+func toRPCOptions(opts Options) enginerpc.Options {
+	return enginerpc.Options{
+		Language:          opts.Language,
+		Final:             opts.Final,
+		Sequence:          opts.Sequence,
+		Prompt:            opts.Prompt,
+		UtteranceOffsetMs: opts.UtteranceOffsetMs,
+		Variant:           opts.Variant,
+	}
+}
+
+func fromRPCResults(results []enginerpc.Result) []Result {
+	if len(results) == 0 {
+		return nil
+	}
+	out := make([]Result, len(results))
+	for i, r := range results {
+		out[i] = Result{
+			Text:             r.Text,
+			Confidence:       r.Confidence,
+			Final:            r.Final,
+			Words:            fromRPCWords(r.Words),
+			AvgLogprob:       r.AvgLogprob,
+			NoSpeechProb:     r.NoSpeechProb,
+			CompressionRatio: r.CompressionRatio,
+		}
+	}
+	return out
+}
+
+func fromRPCWords(words []enginerpc.Word) []Word {
+	if len(words) == 0 {
+		return nil
+	}
+	out := make([]Word, len(words))
+	for i, w := range words {
+		out[i] = Word{Text: w.Text, StartMs: w.StartMs, EndMs: w.EndMs, Probability: w.Probability, SpeakerTurn: w.SpeakerTurn}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/enginerpc"
+)
+
+// GRPCHost adapts an in-process Engine to enginerpc.EngineServiceServer so
+// it can be registered on a *grpc.Server, making that Engine reachable from
+// an adapter configured with Backend = "grpc://host:port" (see
+// NewGRPCEngine). cmd/whisper-worker hosts a native engine this way so heavy
+// models can run on a dedicated GPU box independently of the adapter
+// process.
+type GRPCHost struct {
+	inner Engine
+}
+
+// NewGRPCHost wraps inner so it can be registered with
+// enginerpc.RegisterEngineServiceServer.
+func NewGRPCHost(inner Engine) *GRPCHost {
+	return &GRPCHost{inner: inner}
+}
+
+func (h *GRPCHost) TranscribeSegment(ctx context.Context, req *enginerpc.TranscribeSegmentRequest) (*enginerpc.TranscribeSegmentResponse, error) {
+	results, err := h.inner.TranscribeSegment(ctx, req.Audio, fromRPCOptions(req.Options))
+	if err != nil {
+		return nil, err
+	}
+	return &enginerpc.TranscribeSegmentResponse{Results: toRPCResults(results)}, nil
+}
+
+func (h *GRPCHost) Flush(ctx context.Context, req *enginerpc.FlushRequest) (*enginerpc.FlushResponse, error) {
+	results, err := h.inner.Flush(ctx, fromRPCOptions(req.Options))
+	if err != nil {
+		return nil, err
+	}
+	return &enginerpc.FlushResponse{Results: toRPCResults(results)}, nil
+}
+
+// SetDefaultLanguage is a no-op: the language hint already travels with
+// every TranscribeSegment/Flush call via Options.Language, so the wrapped
+// Engine has no standalone default to update.
+func (h *GRPCHost) SetDefaultLanguage(context.Context, *enginerpc.SetDefaultLanguageRequest) (*enginerpc.Empty, error) {
+	return &enginerpc.Empty{}, nil
+}
+
+func (h *GRPCHost) Close(context.Context, *enginerpc.Empty) (*enginerpc.Empty, error) {
+	return &enginerpc.Empty{}, h.inner.Close()
+}
+
+// GetCapabilities reports which accelerator backends this process was
+// compiled with, so a caller dialing a remote engine can tell what it
+// actually has available without guessing from config alone.
+func (h *GRPCHost) GetCapabilities(context.Context, *enginerpc.Empty) (*enginerpc.CapabilitiesResponse, error) {
+	caps := NativeAvailable()
+	return &enginerpc.CapabilitiesResponse{
+		Native:   caps.Native,
+		Metal:    caps.Metal,
+		CUDA:     caps.CUDA,
+		CoreML:   caps.CoreML,
+		OpenVINO: caps.OpenVINO,
+		BLAS:     caps.BLAS,
+		Vulkan:   caps.Vulkan,
+	}, nil
+}
+
+func fromRPCOptions(opts enginerpc.Options) Options {
+	return Options{
+		Language:          opts.Language,
+		Final:             opts.Final,
+		Sequence:          opts.Sequence,
+		Prompt:            opts.Prompt,
+		UtteranceOffsetMs: opts.UtteranceOffsetMs,
+		Variant:           opts.Variant,
+	}
+}
+
+func toRPCResults(results []Result) []enginerpc.Result {
+	if len(results) == 0 {
+		return nil
+	}
+	out := make([]enginerpc.Result, len(results))
+	for i, r := range results {
+		out[i] = enginerpc.Result{
+			Text:             r.Text,
+			Confidence:       r.Confidence,
+			Final:            r.Final,
+			Words:            toRPCWords(r.Words),
+			AvgLogprob:       r.AvgLogprob,
+			NoSpeechProb:     r.NoSpeechProb,
+			CompressionRatio: r.CompressionRatio,
+		}
+	}
+	return out
+}
+
+func toRPCWords(words []Word) []enginerpc.Word {
+	if len(words) == 0 {
+		return nil
+	}
+	out := make([]enginerpc.Word, len(words))
+	for i, w := range words {
+		out[i] = enginerpc.Word{Text: w.Text, StartMs: w.StartMs, EndMs: w.EndMs, Probability: w.Probability, SpeakerTurn: w.SpeakerTurn}
+	}
+	return out
+}
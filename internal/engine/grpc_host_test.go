@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/enginerpc"
+)
+
+func TestGRPCHostServesStubEngineToGRPCEngine(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	defer lis.Close()
+
+	grpcServer := grpc.NewServer()
+	t.Cleanup(grpcServer.Stop)
+	enginerpc.RegisterEngineServiceServer(grpcServer, NewGRPCHost(NewStubEngine(nil, "base")))
+	go func() { _ = grpcServer.Serve(lis) }()
+
+	client, err := NewGRPCEngine(lis.Addr().String())
+	if err != nil {
+		t.Fatalf("NewGRPCEngine error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	results, err := client.TranscribeSegment(ctx, []byte("abcd"), Options{Sequence: 1})
+	if err != nil {
+		t.Fatalf("TranscribeSegment error: %v", err)
+	}
+	if len(results) != 1 || results[0].Final {
+		t.Fatalf("unexpected partial results: %+v", results)
+	}
+
+	final, err := client.Flush(ctx, Options{Final: true})
+	if err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if len(final) != 1 || !final[0].Final {
+		t.Fatalf("unexpected final results: %+v", final)
+	}
+}
+
+func TestGRPCHostReportsCapabilities(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	defer lis.Close()
+
+	grpcServer := grpc.NewServer()
+	t.Cleanup(grpcServer.Stop)
+	enginerpc.RegisterEngineServiceServer(grpcServer, NewGRPCHost(NewStubEngine(nil, "base")))
+	go func() { _ = grpcServer.Serve(lis) }()
+
+	client, err := NewGRPCEngine(lis.Addr().String())
+	if err != nil {
+		t.Fatalf("NewGRPCEngine error: %v", err)
+	}
+	defer client.Close()
+
+	caps, err := client.GetCapabilities(context.Background())
+	if err != nil {
+		t.Fatalf("GetCapabilities error: %v", err)
+	}
+	if caps != NativeAvailable() {
+		t.Fatalf("expected capabilities to match the host's NativeAvailable(), got %+v", caps)
+	}
+}
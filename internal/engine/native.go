@@ -14,6 +14,7 @@ package engine
 import "C"
 
 import (
+	"container/list"
 	"context"
 	"errors"
 	"fmt"
@@ -24,29 +25,104 @@ import (
 	"strings"
 	"sync"
 	"unsafe"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
 )
 
 const (
+	// minFrameMillis, targetWindowMillis, and keepMillis are the
+	// step_ms/length_ms/keep_ms sliding-window defaults used when
+	// NativeOptions.StepMs/LengthMs/KeepMs are left nil.
 	minFrameMillis      = 3000 // step_ms
 	targetWindowMillis  = 10000
 	keepMillis          = 200
 	defaultFlashAttnEnv = "WHISPERCPP_FLASH_ATTENTION"
 	useGPUEnv           = "WHISPERCPP_USE_GPU"
 	threadsEnv          = "WHISPERCPP_THREADS"
+	maxSessionsEnv      = "WHISPERCPP_MAX_SESSIONS"
+
+	// defaultSessionKey is the session used for calls that leave
+	// Options.SessionID empty (REST uploads, benchmarks, and the existing
+	// single-stream tests), so NativeEngine behaves exactly as it did before
+	// sessions existed when a caller never sets one.
+	defaultSessionKey = ""
+
+	// defaultMaxSessions is the default for NativeOptions.MaxSessions: how
+	// many concurrent whisper_state objects a NativeEngine keeps resident.
+	// Each one owns its own decode buffers on top of the shared model
+	// weights, so an unbounded number of idle streams would otherwise leak
+	// memory for the life of the process.
+	defaultMaxSessions = 64
 )
 
-func NativeAvailable() bool { return true }
+// NativeAvailable reports which accelerator backends this binary was built
+// with. Metal and BLAS (via Accelerate) are wired into the cgo build on
+// darwin (see the darwin LDFLAGS above); CUDA, Core ML, OpenVINO, and Vulkan
+// require linking additional ggml backends and are reported as absent until
+// that lands.
+func NativeAvailable() Capabilities {
+	return Capabilities{
+		Native: true,
+		Metal:  runtime.GOOS == "darwin",
+		BLAS:   runtime.GOOS == "darwin",
+	}
+}
 
+// nativeSession wraps one caller's whisper_stream_state: its own rolling
+// decode buffer and keep_ms history, decoupled from every other session
+// sharing the same whisper_stream context, plus the language/confidence
+// bookkeeping native.go's Go layer needs per caller.
+type nativeSession struct {
+	state *C.whisper_stream_state
+
+	lastConf            float32
+	lastLanguage        string
+	lastDetectLanguage  bool
+	languageConfigured  bool
+	lastTranslate       bool
+	translateConfigured bool
+
+	// refs counts calls currently using state (between acquireCall and its
+	// release). Guarded by NativeEngine.mu.
+	refs int
+	// evicted marks a session sessionLocked has already dropped from
+	// sessions/lru because a newer session needed its slot. state isn't
+	// freed until refs reaches zero, so a goroutine mid-call on it (checked
+	// out moments before the eviction) never has it pulled out from under
+	// it. Guarded by NativeEngine.mu.
+	evicted bool
+}
+
+// NativeEngine binds libwhisper.cpp through cgo. A single whisper_stream
+// holds the shared model context (weights, GPU/Metal backend) loaded once by
+// NewNativeEngine; sessions map Options.SessionID to an independent
+// whisper_stream_state so concurrent gRPC streams decode in parallel instead
+// of serialising on one mutex.
 type NativeEngine struct {
 	mu sync.Mutex
 
 	stream *C.whisper_stream
 
-	defaultLang        string
-	lastConf           float32
-	lastLanguage       string
-	lastDetectLanguage bool
-	languageConfigured bool
+	wordTimestamps bool
+	defaultLang    string
+
+	sessions    map[string]*list.Element // sessionID -> element (Value is *sessionEntry)
+	lru         *list.List
+	maxSessions int
+
+	// closed is set by Close; inFlight counts calls currently running
+	// against stream across every session. stream itself (shared by every
+	// session) is only freed once both hold, so Close racing a goroutine
+	// mid-whisper_stream_process/flush can't free memory that call is still
+	// reading. Guarded by mu.
+	closed   bool
+	inFlight int
+}
+
+// sessionEntry is the list.Element.Value for a resident session.
+type sessionEntry struct {
+	id      string
+	session *nativeSession
 }
 
 func NewNativeEngine(modelPath string, opts NativeOptions) (Engine, error) {
@@ -55,6 +131,9 @@ func NewNativeEngine(modelPath string, opts NativeOptions) (Engine, error) {
 	}
 
 	useGPU := true
+	if opts.Accelerator == config.AcceleratorCPU || opts.Accelerator == config.AcceleratorBLAS {
+		useGPU = false
+	}
 	if opts.UseGPU != nil {
 		useGPU = *opts.UseGPU
 	} else if env := strings.TrimSpace(os.Getenv(useGPUEnv)); env != "" {
@@ -63,6 +142,19 @@ func NewNativeEngine(modelPath string, opts NativeOptions) (Engine, error) {
 		}
 	}
 
+	gpuDevice := int32(-1)
+	if opts.DeviceIndex != nil {
+		gpuDevice = int32(*opts.DeviceIndex)
+	}
+
+	// BLASLibrary has no whisper_context_params equivalent: ggml resolves
+	// its BLAS backend at link time, not at whisper_init. Exporting it as an
+	// environment variable lets a dynamically-linked OpenBLAS pick up a
+	// non-default shared library the way BLAS callers conventionally do.
+	if opts.Accelerator == config.AcceleratorBLAS && strings.TrimSpace(opts.BLASLibrary) != "" {
+		_ = os.Setenv("GGML_BLAS_LIBRARY", opts.BLASLibrary)
+	}
+
 	flashAttn := true
 	if opts.FlashAttention != nil {
 		flashAttn = *opts.FlashAttention
@@ -81,24 +173,63 @@ func NewNativeEngine(modelPath string, opts NativeOptions) (Engine, error) {
 		}
 	}
 
+	stepMillis := minFrameMillis
+	if opts.StepMs != nil && *opts.StepMs > 0 {
+		stepMillis = *opts.StepMs
+	}
+	windowMillis := targetWindowMillis
+	if opts.LengthMs != nil && *opts.LengthMs > 0 {
+		windowMillis = *opts.LengthMs
+	}
+	keepWindowMillis := keepMillis
+	if opts.KeepMs != nil && *opts.KeepMs >= 0 {
+		keepWindowMillis = *opts.KeepMs
+	}
+
+	wordTimestamps := false
+	if opts.WordTimestamps != nil {
+		wordTimestamps = *opts.WordTimestamps
+	}
+
+	tinyDiarize := false
+	if opts.TinyDiarize != nil {
+		tinyDiarize = *opts.TinyDiarize
+	}
+
+	maxSessions := defaultMaxSessions
+	if opts.MaxSessions != nil && *opts.MaxSessions > 0 {
+		maxSessions = *opts.MaxSessions
+	} else if env := strings.TrimSpace(os.Getenv(maxSessionsEnv)); env != "" {
+		if parsed, err := strconv.Atoi(env); err == nil && parsed > 0 {
+			maxSessions = parsed
+		}
+	}
+
 	cModel := C.CString(modelPath)
 	defer C.free(unsafe.Pointer(cModel))
 
 	stream := C.whisper_stream_create(
 		cModel,
-		C.int32_t(minFrameMillis),
-		C.int32_t(targetWindowMillis),
-		C.int32_t(keepMillis),
+		C.int32_t(stepMillis),
+		C.int32_t(windowMillis),
+		C.int32_t(keepWindowMillis),
 		C.int32_t(threads),
 		C.bool(useGPU),
 		C.bool(flashAttn),
+		C.bool(wordTimestamps),
+		C.int32_t(gpuDevice),
+		C.bool(tinyDiarize),
 	)
 	if stream == nil {
 		return nil, fmt.Errorf("whisper: failed to initialise context for %s", modelPath)
 	}
 
 	return &NativeEngine{
-		stream: stream,
+		stream:         stream,
+		wordTimestamps: wordTimestamps,
+		sessions:       make(map[string]*list.Element),
+		lru:            list.New(),
+		maxSessions:    maxSessions,
 	}, nil
 }
 
@@ -115,23 +246,39 @@ func (e *NativeEngine) TranscribeSegment(ctx context.Context, audio []byte, opts
 		return nil, nil
 	}
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	session, release, err := e.acquireCall(opts.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	if err := e.applyLanguageLocked(opts.Language); err != nil {
+	if err := session.applyLanguage(opts.Language, e.defaultLang); err != nil {
+		return nil, err
+	}
+	if err := session.applyTranslate(opts.Translate); err != nil {
 		return nil, err
 	}
 
 	var outText *C.char
 	var outConf C.float
+	var outMeta C.whisper_stream_meta
+	var outWords *C.whisper_stream_word
+	var outWordCount C.int32_t
 
 	rc := C.whisper_stream_process(
 		e.stream,
+		session.state,
 		(*C.float)(unsafe.Pointer(&samples[0])),
 		C.int32_t(len(samples)),
 		&outText,
 		&outConf,
+		&outMeta,
+		&outWords,
+		&outWordCount,
 	)
+	if rc == -2 {
+		return nil, fmt.Errorf("whisper: segment of %d samples would overflow session %q's rolling buffer; send smaller chunks", len(samples), opts.SessionID)
+	}
 	if rc < 0 {
 		return nil, fmt.Errorf("whisper: process error (%d)", int(rc))
 	}
@@ -139,6 +286,9 @@ func (e *NativeEngine) TranscribeSegment(ctx context.Context, audio []byte, opts
 		if outText != nil {
 			C.whisper_stream_free_text(outText)
 		}
+		if outWords != nil {
+			C.whisper_stream_free_words(outWords, outWordCount)
+		}
 		return nil, nil
 	}
 
@@ -146,17 +296,33 @@ func (e *NativeEngine) TranscribeSegment(ctx context.Context, audio []byte, opts
 	C.whisper_stream_free_text(outText)
 	text = strings.TrimSpace(text)
 	if text == "" {
+		if outWords != nil {
+			C.whisper_stream_free_words(outWords, outWordCount)
+		}
 		return nil, nil
 	}
 
 	conf := float32(outConf)
-	e.lastConf = conf
+	session.lastConf = conf
+
+	result := Result{
+		Text:             text,
+		Confidence:       conf,
+		Final:            false,
+		AvgLogprob:       float32(outMeta.avg_logprob),
+		NoSpeechProb:     float32(outMeta.no_speech_prob),
+		CompressionRatio: float32(outMeta.compression_ratio),
+		StartMs:          opts.UtteranceOffsetMs + uint64(outMeta.start_ms),
+		EndMs:            opts.UtteranceOffsetMs + uint64(outMeta.end_ms),
+	}
+	if e.wordTimestamps {
+		result.Words = wordsFromC(outWords, outWordCount, opts.UtteranceOffsetMs)
+	}
+	if outWords != nil {
+		C.whisper_stream_free_words(outWords, outWordCount)
+	}
 
-	return []Result{{
-		Text:       text,
-		Confidence: conf,
-		Final:      false,
-	}}, nil
+	return []Result{result}, nil
 }
 
 func (e *NativeEngine) Flush(ctx context.Context, opts Options) ([]Result, error) {
@@ -164,17 +330,26 @@ func (e *NativeEngine) Flush(ctx context.Context, opts Options) ([]Result, error
 		return nil, err
 	}
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	session, release, err := e.acquireCall(opts.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
-	if err := e.applyLanguageLocked(opts.Language); err != nil {
+	if err := session.applyLanguage(opts.Language, e.defaultLang); err != nil {
+		return nil, err
+	}
+	if err := session.applyTranslate(opts.Translate); err != nil {
 		return nil, err
 	}
 
 	var outText *C.char
 	var outConf C.float
+	var outMeta C.whisper_stream_meta
+	var outWords *C.whisper_stream_word
+	var outWordCount C.int32_t
 
-	rc := C.whisper_stream_flush(e.stream, &outText, &outConf)
+	rc := C.whisper_stream_flush(e.stream, session.state, &outText, &outConf, &outMeta, &outWords, &outWordCount)
 	if rc < 0 {
 		return nil, fmt.Errorf("whisper: flush error (%d)", int(rc))
 	}
@@ -183,26 +358,57 @@ func (e *NativeEngine) Flush(ctx context.Context, opts Options) ([]Result, error
 	C.whisper_stream_free_text(outText)
 	text = strings.TrimSpace(text)
 	if text == "" {
+		if outWords != nil {
+			C.whisper_stream_free_words(outWords, outWordCount)
+		}
 		return nil, nil
 	}
 
 	conf := float32(outConf)
-	e.lastConf = conf
+	session.lastConf = conf
+
+	result := Result{
+		Text:             text,
+		Confidence:       conf,
+		Final:            true,
+		AvgLogprob:       float32(outMeta.avg_logprob),
+		NoSpeechProb:     float32(outMeta.no_speech_prob),
+		CompressionRatio: float32(outMeta.compression_ratio),
+		StartMs:          opts.UtteranceOffsetMs + uint64(outMeta.start_ms),
+		EndMs:            opts.UtteranceOffsetMs + uint64(outMeta.end_ms),
+	}
+	if e.wordTimestamps {
+		result.Words = wordsFromC(outWords, outWordCount, opts.UtteranceOffsetMs)
+	}
+	if outWords != nil {
+		C.whisper_stream_free_words(outWords, outWordCount)
+	}
 
-	return []Result{{
-		Text:       text,
-		Confidence: conf,
-		Final:      true,
-	}}, nil
+	return []Result{result}, nil
 }
 
+// Close releases every resident session and the shared stream. Any call
+// already in flight against them (acquireCall has been called but release
+// has not) keeps its memory alive until that call returns: sessions with
+// refs > 0 are marked evicted instead of freed here, and the stream itself
+// is freed by the last release rather than by Close when inFlight > 0.
 func (e *NativeEngine) Close() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if e.stream != nil {
-		C.whisper_stream_free(e.stream)
-		e.stream = nil
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	for elem := e.lru.Front(); elem != nil; elem = elem.Next() {
+		e.evictEntryLocked(elem.Value.(*sessionEntry))
+	}
+	e.sessions = make(map[string]*list.Element)
+	e.lru = list.New()
+
+	if e.inFlight == 0 {
+		e.freeStreamLocked()
 	}
 	return nil
 }
@@ -210,20 +416,120 @@ func (e *NativeEngine) Close() error {
 func (e *NativeEngine) SetDefaultLanguage(lang string) {
 	e.mu.Lock()
 	e.defaultLang = normaliseLanguageCode(lang)
-	e.languageConfigured = false
+	for elem := e.lru.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*sessionEntry).session.languageConfigured = false
+	}
 	e.mu.Unlock()
 }
 
-func (e *NativeEngine) applyLanguageLocked(lang string) error {
+// sessionLocked returns the nativeSession for id, creating one (and
+// evicting the least-recently-used session if the engine is already at
+// maxSessions) on a miss. Callers must hold e.mu.
+func (e *NativeEngine) sessionLocked(id string) (*nativeSession, error) {
+	if elem, ok := e.sessions[id]; ok {
+		e.lru.MoveToFront(elem)
+		return elem.Value.(*sessionEntry).session, nil
+	}
+
+	if len(e.sessions) >= e.maxSessions {
+		oldest := e.lru.Back()
+		if oldest != nil {
+			entry := oldest.Value.(*sessionEntry)
+			e.evictEntryLocked(entry)
+			e.lru.Remove(oldest)
+			delete(e.sessions, entry.id)
+		}
+	}
+
+	state := C.whisper_stream_new_state(e.stream)
+	if state == nil {
+		return nil, fmt.Errorf("whisper: failed to allocate decoding state for session %q", id)
+	}
+
+	session := &nativeSession{state: state}
+	elem := e.lru.PushFront(&sessionEntry{id: id, session: session})
+	e.sessions[id] = elem
+	return session, nil
+}
+
+// evictEntryLocked drops entry's slot in sessions/lru. If a call is
+// in-flight against its state (refs > 0), freeing is deferred to release
+// once that call returns instead of happening here, so the goroutine
+// running it never has its whisper_stream_state freed out from under it.
+// Callers must hold e.mu.
+func (e *NativeEngine) evictEntryLocked(entry *sessionEntry) {
+	if entry.session.refs > 0 {
+		entry.session.evicted = true
+		return
+	}
+	C.whisper_stream_free_state(entry.session.state)
+}
+
+// acquireCall looks up (or creates) the session for id and marks both it
+// and the engine as having a call in flight, so a concurrent eviction or
+// Close can't free the memory that call is about to read from or write to
+// with cgo. The returned release func must be called exactly once, after
+// the caller is done issuing whisper_stream_* calls against the returned
+// session's state.
+func (e *NativeEngine) acquireCall(id string) (*nativeSession, func(), error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil, nil, errors.New("whisper: engine is closed")
+	}
+
+	session, err := e.sessionLocked(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session.refs++
+	e.inFlight++
+	return session, func() { e.release(session) }, nil
+}
+
+// release undoes the bookkeeping acquireCall did for session, freeing its
+// state (if it was evicted while the call was running) and, once the last
+// in-flight call has returned, the shared stream (if Close ran while calls
+// were still in flight).
+func (e *NativeEngine) release(session *nativeSession) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	session.refs--
+	if session.refs == 0 && session.evicted {
+		C.whisper_stream_free_state(session.state)
+	}
+
+	e.inFlight--
+	if e.inFlight == 0 && e.closed {
+		e.freeStreamLocked()
+	}
+}
+
+// freeStreamLocked releases the shared whisper_stream. Callers must hold
+// e.mu and must only call this once e.inFlight is zero.
+func (e *NativeEngine) freeStreamLocked() {
+	if e.stream != nil {
+		C.whisper_stream_free(e.stream)
+		e.stream = nil
+	}
+}
+
+// applyLanguage configures s's whisper_stream_state language for a segment
+// or flush call. defaultLang is the engine-wide fallback set via
+// SetDefaultLanguage, used when opts.Language is empty or "auto".
+func (s *nativeSession) applyLanguage(lang, defaultLang string) error {
 	hint := strings.TrimSpace(lang)
 	detect := false
 
 	switch {
 	case hint == "":
-		hint = e.defaultLang
+		hint = defaultLang
 	case strings.EqualFold(hint, "auto"):
-		if e.defaultLang != "" {
-			hint = e.defaultLang
+		if defaultLang != "" {
+			hint = defaultLang
 		} else {
 			hint = ""
 		}
@@ -235,7 +541,7 @@ func (e *NativeEngine) applyLanguageLocked(lang string) error {
 		detect = true
 	}
 
-	if e.languageConfigured && e.lastLanguage == hint && e.lastDetectLanguage == detect {
+	if s.languageConfigured && s.lastLanguage == hint && s.lastDetectLanguage == detect {
 		return nil
 	}
 
@@ -245,13 +551,30 @@ func (e *NativeEngine) applyLanguageLocked(lang string) error {
 		defer C.free(unsafe.Pointer(cLang))
 	}
 
-	if rc := C.whisper_stream_set_language(e.stream, cLang, C.bool(detect)); rc != 0 {
+	if rc := C.whisper_stream_set_language(s.state, cLang, C.bool(detect)); rc != 0 {
 		return fmt.Errorf("whisper: set language failed (%d)", int(rc))
 	}
 
-	e.lastLanguage = hint
-	e.lastDetectLanguage = detect
-	e.languageConfigured = true
+	s.lastLanguage = hint
+	s.lastDetectLanguage = detect
+	s.languageConfigured = true
+	return nil
+}
+
+// applyTranslate toggles s's whisper_stream_state between transcription and
+// translation-to-English for a segment or flush call, only crossing into C
+// when translate differs from the session's last configured value.
+func (s *nativeSession) applyTranslate(translate bool) error {
+	if s.translateConfigured && s.lastTranslate == translate {
+		return nil
+	}
+
+	if rc := C.whisper_stream_set_translate(s.state, C.bool(translate)); rc != 0 {
+		return fmt.Errorf("whisper: set translate failed (%d)", int(rc))
+	}
+
+	s.lastTranslate = translate
+	s.translateConfigured = true
 	return nil
 }
 
@@ -266,6 +589,27 @@ func normaliseLanguageCode(lang string) string {
 	return strings.ToLower(trimmed)
 }
 
+// wordsFromC converts whisper.cpp's DTW token-level alignment (utterance-
+// relative, 10ms ticks) into stream-absolute Word timestamps using offsetMs,
+// the segmenter's recorded start time for this utterance.
+func wordsFromC(cWords *C.whisper_stream_word, count C.int32_t, offsetMs uint64) []Word {
+	if cWords == nil || count == 0 {
+		return nil
+	}
+	slice := unsafe.Slice(cWords, int(count))
+	words := make([]Word, 0, int(count))
+	for _, w := range slice {
+		words = append(words, Word{
+			Text:        strings.TrimSpace(C.GoString(w.text)),
+			StartMs:     offsetMs + uint64(w.start_ms)*10,
+			EndMs:       offsetMs + uint64(w.end_ms)*10,
+			Probability: float32(w.probability),
+			SpeakerTurn: bool(w.speaker_turn),
+		})
+	}
+	return words
+}
+
 func pcmBytesToFloat32(buf []byte) []float32 {
 	n := len(buf) / 2
 	if n == 0 {
@@ -8,7 +8,7 @@ import (
 )
 
 func BenchmarkNativeEngineTranscribeSegment(b *testing.B) {
-	if !NativeAvailable() {
+	if !NativeAvailable().Native {
 		b.Skip("native backend not available")
 	}
 
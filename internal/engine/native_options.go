@@ -38,4 +38,25 @@ type NativeOptions struct {
 	MaxTokens *int
 	// TinyDiarize enables the experimental TinyDiARize feature (--tinydiarize).
 	TinyDiarize *bool
+	// Accelerator selects the compute backend to negotiate at runtime; see
+	// config.Accelerator* for the supported values. Empty means
+	// config.AcceleratorAuto.
+	Accelerator string
+	// DeviceIndex pins which GPU NativeEngine initialises against when
+	// Accelerator selects a GPU backend (cuda, vulkan, metal), for hosts with
+	// more than one device. Nil leaves device selection to the backend's
+	// default (device 0).
+	DeviceIndex *int
+	// BLASLibrary is the path to the BLAS shared library NativeEngine should
+	// load when Accelerator is config.AcceleratorBLAS. Empty uses whichever
+	// BLAS implementation the binary was linked against at build time.
+	BLASLibrary string
+	// WordTimestamps enables whisper's DTW token-level alignment so
+	// Result.Words is populated.
+	WordTimestamps *bool
+	// MaxSessions bounds how many concurrent whisper_state objects
+	// NativeEngine keeps resident, evicting the least-recently-used one
+	// past this limit. Nil falls back to the WHISPERCPP_MAX_SESSIONS env
+	// var, then a built-in default.
+	MaxSessions *int
 }
@@ -4,11 +4,11 @@ package engine
 
 import "context"
 
-// NativeAvailable reports whether the native whisper backend is compiled in.
-func NativeAvailable() bool { return false }
+// NativeAvailable reports that no accelerator backend is compiled in.
+func NativeAvailable() Capabilities { return Capabilities{} }
 
 // NewNativeEngine returns an error when the native backend is not built.
-func NewNativeEngine(modelPath string) (Engine, error) {
+func NewNativeEngine(modelPath string, opts NativeOptions) (Engine, error) {
 	return nil, ErrNativeEngineUnavailable
 }
 
@@ -11,10 +11,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNativeEngineTranscribesFixture(t *testing.T) {
-	if !NativeAvailable() {
+	if !NativeAvailable().Native {
 		t.Skip("native backend not available")
 	}
 
@@ -97,7 +98,7 @@ func TestNativeEngineTranscribesFixture(t *testing.T) {
 }
 
 func TestNativeEngineAutoLanguageFallsBack(t *testing.T) {
-	if !NativeAvailable() {
+	if !NativeAvailable().Native {
 		t.Skip("native backend not available")
 	}
 
@@ -140,7 +141,7 @@ func TestNativeEngineAutoLanguageFallsBack(t *testing.T) {
 }
 
 func TestNativeEngineTranscribeSegmentRespectsContextCancellation(t *testing.T) {
-	if !NativeAvailable() {
+	if !NativeAvailable().Native {
 		t.Skip("native backend not available")
 	}
 
@@ -159,7 +160,7 @@ func TestNativeEngineTranscribeSegmentRespectsContextCancellation(t *testing.T)
 }
 
 func TestNativeEngineTrimsOversizedAudio(t *testing.T) {
-	if !NativeAvailable() {
+	if !NativeAvailable().Native {
 		t.Skip("native backend not available")
 	}
 
@@ -187,6 +188,66 @@ func TestNewNativeEngineRejectsEmptyPath(t *testing.T) {
 	}
 }
 
+func TestNativeEngineEvictionKeepsInFlightSessionAlive(t *testing.T) {
+	if !NativeAvailable().Native {
+		t.Skip("native backend not available")
+	}
+
+	engine := openTestNativeEngine(t)
+	engine.maxSessions = 1
+
+	session, release, err := engine.acquireCall("first")
+	if err != nil {
+		t.Fatalf("acquireCall(first): %v", err)
+	}
+
+	// maxSessions is 1, so this evicts "first" while its call above is
+	// still in flight (release hasn't run yet). That must not free
+	// session.state out from under it.
+	if _, release2, err := engine.acquireCall("second"); err != nil {
+		t.Fatalf("acquireCall(second): %v", err)
+	} else {
+		release2()
+	}
+
+	if !session.evicted {
+		t.Fatal("expected first session to be marked evicted once second took its slot")
+	}
+	if session.refs != 1 {
+		t.Fatalf("expected evicted-but-in-flight session to keep refs=1, got %d", session.refs)
+	}
+
+	release()
+}
+
+func TestNativeEngineCloseWaitsForInFlightCall(t *testing.T) {
+	if !NativeAvailable().Native {
+		t.Skip("native backend not available")
+	}
+
+	engine := openTestNativeEngine(t)
+
+	_, release, err := engine.acquireCall(defaultSessionKey)
+	if err != nil {
+		t.Fatalf("acquireCall: %v", err)
+	}
+
+	closeDone := make(chan error, 1)
+	go func() { closeDone <- engine.Close() }()
+
+	select {
+	case <-closeDone:
+		t.Fatal("Close returned before the in-flight call released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+
+	if err := <-closeDone; err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
 func openTestNativeEngine(tb testing.TB) *NativeEngine {
 	tb.Helper()
 
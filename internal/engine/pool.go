@@ -0,0 +1,386 @@
+package engine
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/segmenter"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/models"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// DefaultVariantSetter is implemented by Engine backends that support
+// retargeting routing's default variant at runtime, such as EnginePool. A
+// caller (e.g. cmd/adapter's SIGHUP handler) type-asserts Engine against
+// this interface rather than depending on EnginePool directly, since a
+// single-model backend has no notion of a default to swap.
+type DefaultVariantSetter interface {
+	SetDefaultVariant(variant string) error
+}
+
+// EnginePool fronts several lazily-loaded native engines, one per
+// config.ModelPoolEntry, behind a single Engine façade. TranscribeSegment and
+// Flush dispatch to the entry named by Options.Variant when set, otherwise to
+// the entry whose Languages include the resolved Options.Language, otherwise
+// to the pool's default variant. At most MaxResidentModels entries are kept
+// loaded at once; resolveAndLoad evicts the least-recently-used loaded entry
+// before loading another.
+type EnginePool struct {
+	cfg     config.Config
+	manager *models.Manager
+	log     *slog.Logger
+	metrics *telemetry.Recorder
+
+	segCfg             segmenter.Config
+	promptContextChars int
+
+	byVariant        map[string]*config.ModelPoolEntry
+	order            []config.ModelPoolEntry
+	defaultVariant   atomic.Pointer[string]
+	maxResident      int
+	maxResidentBytes int64
+
+	mu            sync.Mutex
+	loaded        map[string]*list.Element // variant -> element in lru (Value is *loadedEngine)
+	lru           *list.List
+	residentBytes int64
+}
+
+// loadedEngine is the list.Element.Value for a resident pool entry.
+type loadedEngine struct {
+	variant   string
+	engine    Engine
+	sizeBytes int64
+}
+
+// NewEnginePool builds an EnginePool from cfg.Models. manager resolves each
+// entry's model path the same way a single-model engine.New would; metrics
+// records pool hit/miss counters. It returns an error if cfg.Models is empty
+// or any entry is missing a variant name.
+func NewEnginePool(cfg config.Config, manager *models.Manager, logger *slog.Logger, metrics *telemetry.Recorder) (*EnginePool, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if metrics == nil {
+		metrics = telemetry.NewRecorder(logger)
+	}
+	if len(cfg.Models) == 0 {
+		return nil, fmt.Errorf("engine: model pool requires at least one NUPI_ADAPTER_MODELS entry")
+	}
+
+	byVariant := make(map[string]*config.ModelPoolEntry, len(cfg.Models))
+	for i := range cfg.Models {
+		entry := cfg.Models[i]
+		if strings.TrimSpace(entry.Variant) == "" {
+			return nil, fmt.Errorf("engine: model pool entry %d has no variant", i)
+		}
+		if _, exists := byVariant[entry.Variant]; exists {
+			return nil, fmt.Errorf("engine: model pool has duplicate variant %q", entry.Variant)
+		}
+		byVariant[entry.Variant] = &cfg.Models[i]
+	}
+
+	defaultVariant := cfg.ModelVariant
+	if _, ok := byVariant[defaultVariant]; !ok {
+		defaultVariant = cfg.Models[0].Variant
+	}
+
+	maxResident := cfg.MaxResidentModels
+	if maxResident <= 0 {
+		maxResident = len(cfg.Models)
+	}
+
+	pool := &EnginePool{
+		cfg:     cfg,
+		manager: manager,
+		log:     logger.With("component", "engine.pool"),
+		metrics: metrics,
+
+		segCfg: segmenter.Config{
+			MinSilenceMs:   cfg.MinSilenceMs,
+			MaxUtteranceMs: cfg.MaxUtteranceMs,
+		},
+		promptContextChars: cfg.PromptContextChars,
+
+		byVariant:        byVariant,
+		order:            cfg.Models,
+		maxResident:      maxResident,
+		maxResidentBytes: cfg.MaxResidentBytes,
+
+		loaded: make(map[string]*list.Element),
+		lru:    list.New(),
+	}
+	pool.defaultVariant.Store(&defaultVariant)
+	return pool, nil
+}
+
+// Variants lists the pool's configured variant names in NUPI_ADAPTER_MODELS
+// order, for logging.
+func (p *EnginePool) Variants() []string {
+	names := make([]string, len(p.order))
+	for i, entry := range p.order {
+		names[i] = entry.Variant
+	}
+	return names
+}
+
+// TranscribeSegment implements the Engine interface, routing to the entry
+// resolved from opts.
+func (p *EnginePool) TranscribeSegment(ctx context.Context, audio []byte, opts Options) ([]Result, error) {
+	eng, err := p.engineFor(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return eng.TranscribeSegment(ctx, audio, opts)
+}
+
+// Flush implements the Engine interface, routing to the entry resolved from
+// opts.
+func (p *EnginePool) Flush(ctx context.Context, opts Options) ([]Result, error) {
+	eng, err := p.engineFor(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return eng.Flush(ctx, opts)
+}
+
+// Close releases every resident engine.
+func (p *EnginePool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var firstErr error
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		le := elem.Value.(*loadedEngine)
+		if err := le.engine.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	p.loaded = make(map[string]*list.Element)
+	p.lru = list.New()
+	return firstErr
+}
+
+// resolveVariant picks the entry that should handle opts: an explicit
+// opts.Variant wins outright, then the first configured entry whose
+// Languages include opts.Language, then the pool's default.
+func (p *EnginePool) resolveVariant(opts Options) string {
+	if variant := strings.TrimSpace(opts.Variant); variant != "" {
+		if _, ok := p.byVariant[variant]; ok {
+			return variant
+		}
+	}
+
+	language := strings.TrimSpace(opts.Language)
+	if language != "" && !strings.EqualFold(language, "auto") {
+		for _, entry := range p.order {
+			for _, candidate := range entry.Languages {
+				if strings.EqualFold(candidate, language) {
+					return entry.Variant
+				}
+			}
+		}
+	}
+
+	return *p.defaultVariant.Load()
+}
+
+// DefaultVariant returns the variant currently used as resolveVariant's
+// fallback when opts carries neither an explicit Variant nor a Language
+// match.
+func (p *EnginePool) DefaultVariant() string {
+	return *p.defaultVariant.Load()
+}
+
+// SetDefaultVariant implements DefaultVariantSetter, atomically retargeting
+// resolveVariant's fallback to variant. Streams already dispatched to the
+// previous default keep their engine reference (engineFor is only consulted
+// again on the next TranscribeSegment/Flush call), so in-flight streams are
+// unaffected; only new ones observe the swap.
+func (p *EnginePool) SetDefaultVariant(variant string) error {
+	variant = strings.TrimSpace(variant)
+	if _, ok := p.byVariant[variant]; !ok {
+		return fmt.Errorf("engine: model pool has no variant %q", variant)
+	}
+	p.defaultVariant.Store(&variant)
+	return nil
+}
+
+// LoadVariant eagerly warms variant into the resident set, evicting the
+// least-recently-used entry first if necessary. It is a no-op if variant is
+// already resident. Callers use this (e.g. an admin RPC or SIGHUP handler) to
+// pay a model's load latency ahead of the first request that needs it.
+func (p *EnginePool) LoadVariant(ctx context.Context, variant string) error {
+	_, err := p.engineFor(ctx, Options{Variant: variant})
+	return err
+}
+
+// UnloadVariant drops variant from the resident set and closes its engine,
+// if resident. It is a no-op if variant isn't currently loaded. Unlike
+// evictLocked, this is always admin-driven rather than a side effect of
+// loading another variant.
+func (p *EnginePool) UnloadVariant(variant string) error {
+	p.mu.Lock()
+	elem, ok := p.loaded[variant]
+	if !ok {
+		p.mu.Unlock()
+		return nil
+	}
+	le := elem.Value.(*loadedEngine)
+	p.lru.Remove(elem)
+	delete(p.loaded, variant)
+	p.residentBytes -= le.sizeBytes
+	p.mu.Unlock()
+
+	p.metrics.RecordModelEvicted(variant)
+	p.log.Info("unloaded pool engine", "variant", variant)
+	return le.engine.Close()
+}
+
+// engineFor returns the resident engine for the variant opts resolves to,
+// lazy-loading it (and evicting the least-recently-used resident entry, if
+// the pool is already at MaxResidentModels or MaxResidentBytes) on a miss.
+func (p *EnginePool) engineFor(ctx context.Context, opts Options) (Engine, error) {
+	variant := p.resolveVariant(opts)
+
+	p.mu.Lock()
+	if elem, ok := p.loaded[variant]; ok {
+		p.lru.MoveToFront(elem)
+		eng := elem.Value.(*loadedEngine).engine
+		p.mu.Unlock()
+		p.metrics.RecordPoolLookup(variant, true)
+		return eng, nil
+	}
+	p.mu.Unlock()
+
+	p.metrics.RecordPoolLookup(variant, false)
+
+	entry, ok := p.byVariant[variant]
+	if !ok {
+		return nil, fmt.Errorf("engine: model pool has no variant %q", variant)
+	}
+
+	eng, sizeBytes, err := p.loadEntry(ctx, *entry)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Another caller may have loaded the same variant while loadEntry ran
+	// without the lock held; keep whichever instance won the race and close
+	// the loser so the engine and its model resources aren't leaked.
+	if elem, ok := p.loaded[variant]; ok {
+		p.lru.MoveToFront(elem)
+		winner := elem.Value.(*loadedEngine).engine
+		if closeErr := eng.Close(); closeErr != nil {
+			p.log.Warn("failed to close redundant pool engine", "variant", variant, "error", closeErr)
+		}
+		return winner, nil
+	}
+
+	p.evictLocked(sizeBytes)
+	elem := p.lru.PushFront(&loadedEngine{variant: variant, engine: eng, sizeBytes: sizeBytes})
+	p.loaded[variant] = elem
+	p.residentBytes += sizeBytes
+	p.metrics.RecordModelLoaded(variant)
+	return eng, nil
+}
+
+// evictLocked closes and drops the least-recently-used resident engine(s)
+// until there is room for one more entry of incomingBytes, per
+// MaxResidentModels and, if set, MaxResidentBytes. Callers must hold p.mu.
+func (p *EnginePool) evictLocked(incomingBytes int64) {
+	for len(p.loaded) >= p.maxResident || (p.maxResidentBytes > 0 && p.residentBytes+incomingBytes > p.maxResidentBytes) {
+		back := p.lru.Back()
+		if back == nil {
+			return
+		}
+		le := back.Value.(*loadedEngine)
+		p.lru.Remove(back)
+		delete(p.loaded, le.variant)
+		p.residentBytes -= le.sizeBytes
+		if err := le.engine.Close(); err != nil {
+			p.log.Warn("failed to close evicted pool engine", "variant", le.variant, "error", err)
+		}
+		p.metrics.RecordModelEvicted(le.variant)
+		p.log.Info("evicted pool engine", "variant", le.variant)
+	}
+}
+
+// loadEntry resolves entry's model path through p.manager and instantiates
+// its native engine, wrapping it with VAD-driven segmentation when
+// p.cfg.VADMode is energy, mirroring newEngineWithOptions' single-model
+// native backend setup. It also returns the resolved model file's size in
+// bytes, for MaxResidentBytes accounting; a stat failure is logged but not
+// fatal, since a pool with MaxResidentBytes unset doesn't need the figure.
+func (p *EnginePool) loadEntry(ctx context.Context, entry config.ModelPoolEntry) (Engine, int64, error) {
+	if !NativeAvailable().Native {
+		return nil, 0, fmt.Errorf("engine: pool variant %q requires a native backend, but none is compiled in", entry.Variant)
+	}
+	if p.manager == nil {
+		return nil, 0, fmt.Errorf("engine: pool variant %q requires a model manager", entry.Variant)
+	}
+
+	manifest, err := models.DefaultManifest()
+	if err != nil {
+		return nil, 0, fmt.Errorf("engine: pool variant %q: %w", entry.Variant, err)
+	}
+
+	modelPath, err := p.manager.EnsureVariant(ctx, entry.Variant, models.EnsureOptions{
+		Manifest:    manifest,
+		Override:    entry.Path,
+		Accelerator: p.cfg.Accelerator,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("engine: pool variant %q: %w", entry.Variant, err)
+	}
+
+	var sizeBytes int64
+	if info, statErr := os.Stat(modelPath); statErr != nil {
+		p.log.Warn("failed to stat pool model file", "variant", entry.Variant, "path", modelPath, "error", statErr)
+	} else {
+		sizeBytes = info.Size()
+	}
+
+	useGPU := p.cfg.UseGPU
+	if entry.UseGPU != nil {
+		useGPU = entry.UseGPU
+	}
+	flashAttention := p.cfg.FlashAttention
+	if entry.FlashAttention != nil {
+		flashAttention = entry.FlashAttention
+	}
+	threads := p.cfg.Threads
+	if entry.Threads != nil {
+		threads = entry.Threads
+	}
+
+	native, err := NewNativeEngine(modelPath, NativeOptions{
+		UseGPU:         useGPU,
+		FlashAttention: flashAttention,
+		Threads:        threads,
+		Accelerator:    p.cfg.Accelerator,
+		DeviceIndex:    p.cfg.DeviceIndex,
+		BLASLibrary:    p.cfg.BLASLibrary,
+		WordTimestamps: &p.cfg.WordTimestamps,
+		TinyDiarize:    &p.cfg.Diarization,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("engine: pool variant %q: %w", entry.Variant, err)
+	}
+
+	p.log.Info("loaded pool engine", "variant", entry.Variant, "model_path", modelPath, "size_bytes", sizeBytes, "languages", entry.Languages)
+
+	if p.cfg.VADMode == config.VADModeEnergy {
+		return NewSegmentedEngine(native, p.segCfg, p.promptContextChars), sizeBytes, nil
+	}
+	return native, sizeBytes, nil
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+)
+
+func TestNewEnginePoolRejectsEmptyModels(t *testing.T) {
+	if _, err := NewEnginePool(config.Config{}, nil, nil, nil); err == nil {
+		t.Fatalf("expected error for empty Models")
+	}
+}
+
+func TestNewEnginePoolRejectsMissingVariant(t *testing.T) {
+	cfg := config.Config{Models: []config.ModelPoolEntry{{Languages: []string{"en"}}}}
+	if _, err := NewEnginePool(cfg, nil, nil, nil); err == nil {
+		t.Fatalf("expected error for entry with no variant")
+	}
+}
+
+func TestNewEnginePoolRejectsDuplicateVariant(t *testing.T) {
+	cfg := config.Config{Models: []config.ModelPoolEntry{
+		{Variant: "base"},
+		{Variant: "base"},
+	}}
+	if _, err := NewEnginePool(cfg, nil, nil, nil); err == nil {
+		t.Fatalf("expected error for duplicate variant")
+	}
+}
+
+func TestNewEnginePoolDefaultVariantFallsBackToFirstEntry(t *testing.T) {
+	cfg := config.Config{
+		ModelVariant: "does-not-exist",
+		Models: []config.ModelPoolEntry{
+			{Variant: "tiny.en"},
+			{Variant: "large-v3"},
+		},
+	}
+	pool, err := NewEnginePool(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEnginePool error: %v", err)
+	}
+	if got := pool.DefaultVariant(); got != "tiny.en" {
+		t.Fatalf("DefaultVariant() = %q, want %q", got, "tiny.en")
+	}
+}
+
+func TestEnginePoolSetDefaultVariant(t *testing.T) {
+	cfg := config.Config{
+		ModelVariant: "base",
+		Models: []config.ModelPoolEntry{
+			{Variant: "base"},
+			{Variant: "large-v3"},
+		},
+	}
+	pool, err := NewEnginePool(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEnginePool error: %v", err)
+	}
+
+	if err := pool.SetDefaultVariant("large-v3"); err != nil {
+		t.Fatalf("SetDefaultVariant error: %v", err)
+	}
+	if got := pool.DefaultVariant(); got != "large-v3" {
+		t.Fatalf("DefaultVariant() = %q, want %q", got, "large-v3")
+	}
+	if got := pool.resolveVariant(Options{}); got != "large-v3" {
+		t.Fatalf("resolveVariant() after swap = %q, want %q", got, "large-v3")
+	}
+
+	if err := pool.SetDefaultVariant("does-not-exist"); err == nil {
+		t.Fatalf("expected error for unknown variant")
+	}
+	if got := pool.DefaultVariant(); got != "large-v3" {
+		t.Fatalf("DefaultVariant() after rejected swap = %q, want %q", got, "large-v3")
+	}
+}
+
+func TestEnginePoolUnloadVariantNotResidentIsNoop(t *testing.T) {
+	cfg := config.Config{Models: []config.ModelPoolEntry{{Variant: "base"}}}
+	pool, err := NewEnginePool(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEnginePool error: %v", err)
+	}
+	if err := pool.UnloadVariant("base"); err != nil {
+		t.Fatalf("UnloadVariant on a never-loaded variant: %v", err)
+	}
+}
+
+func TestEnginePoolResolveVariant(t *testing.T) {
+	cfg := config.Config{
+		ModelVariant: "base",
+		Models: []config.ModelPoolEntry{
+			{Variant: "base", Languages: []string{"en"}},
+			{Variant: "multi", Languages: []string{"pl", "de"}},
+			{Variant: "large-v3"},
+		},
+	}
+	pool, err := NewEnginePool(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEnginePool error: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"explicit variant wins", Options{Variant: "large-v3", Language: "en"}, "large-v3"},
+		{"unknown explicit variant falls through to language", Options{Variant: "nope", Language: "de"}, "multi"},
+		{"language match is case-insensitive", Options{Language: "DE"}, "multi"},
+		{"auto language falls back to default", Options{Language: "auto"}, "base"},
+		{"no match falls back to default", Options{Language: "fr"}, "base"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := pool.resolveVariant(tc.opts); got != tc.want {
+				t.Fatalf("resolveVariant(%+v) = %q, want %q", tc.opts, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnginePoolVariants(t *testing.T) {
+	cfg := config.Config{Models: []config.ModelPoolEntry{
+		{Variant: "tiny.en"},
+		{Variant: "base"},
+		{Variant: "large-v3"},
+	}}
+	pool, err := NewEnginePool(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEnginePool error: %v", err)
+	}
+	got := pool.Variants()
+	want := []string{"tiny.en", "base", "large-v3"}
+	if len(got) != len(want) {
+		t.Fatalf("Variants() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Variants()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEnginePoolMaxResidentDefaultsToModelCount(t *testing.T) {
+	cfg := config.Config{Models: []config.ModelPoolEntry{{Variant: "a"}, {Variant: "b"}}}
+	pool, err := NewEnginePool(cfg, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewEnginePool error: %v", err)
+	}
+	if pool.maxResident != 2 {
+		t.Fatalf("maxResident = %d, want 2", pool.maxResident)
+	}
+}
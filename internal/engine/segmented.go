@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/segmenter"
+)
+
+// segmentedSession is one stream's energy-VAD segmentation state: its
+// segmenter, the prompt context carried forward from the previous
+// utterance, and the stream time represented by audio pushed so far.
+// segmenter.Segmenter "is not safe for concurrent use", so every session
+// gets its own instead of SegmentedEngine sharing one across every stream.
+type segmentedSession struct {
+	mu        sync.Mutex
+	seg       *segmenter.Segmenter
+	prompt    string
+	elapsedMs uint64
+}
+
+// SegmentedEngine wraps an inner Engine with energy-based VAD segmentation.
+// Instead of handing every fixed-size chunk straight to the inner engine, it
+// buffers audio until segmenter reports an utterance boundary, then decodes
+// the full utterance as a final, biasing the decode with the previous
+// utterance's transcript as prompt context. Mid-utterance, it still runs a
+// lightweight decode over the growing buffer so callers get low-latency
+// partials. State is kept per opts.SessionID so concurrent streams don't
+// corrupt each other's rolling buffer and prompt context.
+type SegmentedEngine struct {
+	inner              Engine
+	segConfig          segmenter.Config
+	promptContextChars int
+
+	mu       sync.Mutex
+	sessions map[string]*segmentedSession
+}
+
+// pcmBytesPerMs is the byte rate of 16-bit mono PCM sampled at 16kHz, used to
+// translate buffered audio lengths into stream-time milliseconds.
+const pcmBytesPerMs = 2 * 16000 / 1000
+
+func pcmDurationMs(n int) uint64 {
+	return uint64(n) / pcmBytesPerMs
+}
+
+// NewSegmentedEngine wraps inner with VAD-driven segmentation using cfg.
+// promptContextChars bounds how much of the previous final transcript is
+// kept as prompt context for the next utterance.
+func NewSegmentedEngine(inner Engine, cfg segmenter.Config, promptContextChars int) *SegmentedEngine {
+	return &SegmentedEngine{
+		inner:              inner,
+		segConfig:          cfg,
+		promptContextChars: promptContextChars,
+		sessions:           make(map[string]*segmentedSession),
+	}
+}
+
+// session returns sessionID's segmentedSession, creating one with a fresh
+// Segmenter if this is the first audio seen for it.
+func (e *SegmentedEngine) session(sessionID string) *segmentedSession {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s, ok := e.sessions[sessionID]
+	if !ok {
+		s = &segmentedSession{seg: segmenter.New(e.segConfig)}
+		e.sessions[sessionID] = s
+	}
+	return s
+}
+
+// forgetSession drops sessionID's state. Called once its stream has ended
+// (Flush, or a TranscribeSegment whose Options.Final is set), since a new
+// stream reusing the same session ID should start from a clean Segmenter
+// rather than inheriting a finished stream's prompt and elapsed time.
+func (e *SegmentedEngine) forgetSession(sessionID string) {
+	e.mu.Lock()
+	delete(e.sessions, sessionID)
+	e.mu.Unlock()
+}
+
+// TranscribeSegment implements the Engine interface. audio is raw PCM16LE
+// mono audio appended to the in-progress utterance; opts.Final forces the
+// segmenter to close out the utterance regardless of detected silence.
+func (e *SegmentedEngine) TranscribeSegment(ctx context.Context, audio []byte, opts Options) ([]Result, error) {
+	s := e.session(opts.SessionID)
+	s.mu.Lock()
+	s.elapsedMs += pcmDurationMs(len(audio))
+	event := s.seg.Push(audio)
+	if opts.Final {
+		event = segmenter.EventFinal
+	}
+	results, err := e.decodeLocked(ctx, s, event, opts)
+	if opts.Final {
+		e.forgetSession(opts.SessionID)
+	}
+	return results, err
+}
+
+// Flush implements the Engine interface, closing out any in-progress
+// utterance as a final and forgetting opts.SessionID's state.
+func (e *SegmentedEngine) Flush(ctx context.Context, opts Options) ([]Result, error) {
+	s := e.session(opts.SessionID)
+	defer e.forgetSession(opts.SessionID)
+
+	s.mu.Lock()
+	if len(s.seg.Audio()) == 0 {
+		s.mu.Unlock()
+		return e.inner.Flush(ctx, opts)
+	}
+	return e.decodeLocked(ctx, s, segmenter.EventFinal, opts)
+}
+
+// decodeLocked runs the inner engine over s's buffered audio according to
+// event, and must be called with s.mu held; it always unlocks before
+// returning.
+func (e *SegmentedEngine) decodeLocked(ctx context.Context, s *segmentedSession, event segmenter.Event, opts Options) ([]Result, error) {
+	switch event {
+	case segmenter.EventNone:
+		s.mu.Unlock()
+		return nil, nil
+	case segmenter.EventPartial:
+		audio := s.seg.Audio()
+		prompt := s.prompt
+		offsetMs := s.elapsedMs - pcmDurationMs(len(audio))
+		s.mu.Unlock()
+		partialOpts := opts
+		partialOpts.Final = false
+		partialOpts.Prompt = prompt
+		partialOpts.UtteranceOffsetMs = offsetMs
+		return e.inner.TranscribeSegment(ctx, audio, partialOpts)
+	default: // segmenter.EventFinal
+		audio := s.seg.Audio()
+		prompt := s.prompt
+		offsetMs := s.elapsedMs - pcmDurationMs(len(audio))
+		s.seg.Reset()
+		s.mu.Unlock()
+
+		finalOpts := opts
+		finalOpts.Final = true
+		finalOpts.Prompt = prompt
+		finalOpts.UtteranceOffsetMs = offsetMs
+		results, err := e.inner.TranscribeSegment(ctx, audio, finalOpts)
+		if err != nil {
+			return nil, err
+		}
+		e.updatePrompt(s, results)
+		return results, nil
+	}
+}
+
+// updatePrompt carries the last final transcript forward, truncated to
+// promptContextChars, so the next utterance's decode has continuity context.
+func (e *SegmentedEngine) updatePrompt(s *segmentedSession, results []Result) {
+	for i := len(results) - 1; i >= 0; i-- {
+		if !results[i].Final {
+			continue
+		}
+		text := strings.TrimSpace(results[i].Text)
+		if e.promptContextChars > 0 && len(text) > e.promptContextChars {
+			text = text[len(text)-e.promptContextChars:]
+		}
+		s.mu.Lock()
+		s.prompt = text
+		s.mu.Unlock()
+		return
+	}
+}
+
+// Close implements the Engine interface, releasing the inner engine.
+func (e *SegmentedEngine) Close() error {
+	return e.inner.Close()
+}
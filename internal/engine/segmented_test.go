@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine/segmenter"
+)
+
+// recordingSegmentedInner is a fake inner Engine for SegmentedEngine tests.
+// It records every TranscribeSegment call's Options and audio length per
+// session, and blocks a call on a per-session gate until released, so a
+// test can hold one session's decode open while driving another.
+type recordingSegmentedInner struct {
+	mu        sync.Mutex
+	calls     map[string][]Options
+	audioLens map[string][]int
+	gates     map[string]chan struct{}
+	gated     bool
+}
+
+func newRecordingSegmentedInner(gated bool) *recordingSegmentedInner {
+	return &recordingSegmentedInner{
+		calls:     make(map[string][]Options),
+		audioLens: make(map[string][]int),
+		gates:     make(map[string]chan struct{}),
+		gated:     gated,
+	}
+}
+
+func (e *recordingSegmentedInner) gate(sessionID string) chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ch, ok := e.gates[sessionID]
+	if !ok {
+		ch = make(chan struct{})
+		e.gates[sessionID] = ch
+	}
+	return ch
+}
+
+func (e *recordingSegmentedInner) release(sessionID string) {
+	close(e.gate(sessionID))
+}
+
+func (e *recordingSegmentedInner) callCount(sessionID string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.calls[sessionID])
+}
+
+func (e *recordingSegmentedInner) TranscribeSegment(ctx context.Context, audio []byte, opts Options) ([]Result, error) {
+	e.mu.Lock()
+	e.calls[opts.SessionID] = append(e.calls[opts.SessionID], opts)
+	e.audioLens[opts.SessionID] = append(e.audioLens[opts.SessionID], len(audio))
+	e.mu.Unlock()
+
+	if e.gated {
+		<-e.gate(opts.SessionID)
+	}
+	return []Result{{Text: "result:" + opts.SessionID, Final: opts.Final}}, nil
+}
+
+func (e *recordingSegmentedInner) Flush(ctx context.Context, opts Options) ([]Result, error) {
+	return []Result{{Text: "flush:" + opts.SessionID, Final: true}}, nil
+}
+
+func (e *recordingSegmentedInner) Close() error { return nil }
+
+// loudFrame20ms returns one 20ms frame (frameMs in the segmenter package) of
+// PCM16LE mono audio well above any reasonable EnergyThreshold, so Push
+// always classifies it as speech regardless of test timing.
+func loudFrame20ms() []byte {
+	frame := make([]byte, 640) // 16kHz * 2 bytes/sample * 20ms / 1000
+	for i := 0; i < len(frame); i += 2 {
+		binary.LittleEndian.PutUint16(frame[i:], uint16(16000))
+	}
+	return frame
+}
+
+func testSegmenterConfig() segmenter.Config {
+	return segmenter.Config{
+		EnergyThreshold: 0.02,
+		MinSpeechMs:     20,
+		MinSilenceMs:    10000,
+		MaxUtteranceMs:  1000000,
+	}
+}
+
+// TestSegmentedEngineKeysStateBySessionID interleaves two sessions' pushes
+// the way a server fanning out across concurrent streams would: session-a's
+// first frame, then session-b's first frame, then each session's
+// forced-final frame. A Segmenter/prompt/elapsedMs shared across every
+// session (instead of keyed by opts.SessionID) would bleed session-b's
+// audio into session-a's rolling buffer and vice versa.
+func TestSegmentedEngineKeysStateBySessionID(t *testing.T) {
+	inner := newRecordingSegmentedInner(false)
+	eng := NewSegmentedEngine(inner, testSegmenterConfig(), 0)
+	ctx := context.Background()
+	frame := loudFrame20ms()
+
+	mustTranscribe := func(sessionID string, final bool) {
+		t.Helper()
+		if _, err := eng.TranscribeSegment(ctx, frame, Options{SessionID: sessionID, Final: final}); err != nil {
+			t.Fatalf("%s: %v", sessionID, err)
+		}
+	}
+	mustTranscribe("session-a", false)
+	mustTranscribe("session-b", false)
+	mustTranscribe("session-a", true)
+	mustTranscribe("session-b", true)
+
+	for _, sessionID := range []string{"session-a", "session-b"} {
+		calls := inner.calls[sessionID]
+		if len(calls) != 2 {
+			t.Fatalf("%s: expected 2 inner calls, got %d", sessionID, len(calls))
+		}
+		for _, opts := range calls {
+			if opts.SessionID != sessionID {
+				t.Fatalf("call recorded under %s carried mismatched SessionID %q", sessionID, opts.SessionID)
+			}
+		}
+		lens := inner.audioLens[sessionID]
+		if lens[1] != len(frame)*2 {
+			t.Fatalf("%s: expected its final call to carry exactly its own 2 buffered frames (%d bytes), got %d -- another session's audio leaked into its buffer", sessionID, len(frame)*2, lens[1])
+		}
+	}
+}
+
+// TestSegmentedEngineConcurrentSessionsDoNotSerialize starts two sessions
+// concurrently against an inner engine that blocks until released, and
+// asserts both reach the inner engine without one waiting on the other,
+// the same property chunk2-1 verified for NativeEngine.
+func TestSegmentedEngineConcurrentSessionsDoNotSerialize(t *testing.T) {
+	inner := newRecordingSegmentedInner(true)
+	eng := NewSegmentedEngine(inner, testSegmenterConfig(), 0)
+	ctx := context.Background()
+	frame := loudFrame20ms()
+
+	doneA := make(chan error, 1)
+	doneB := make(chan error, 1)
+	go func() {
+		_, err := eng.TranscribeSegment(ctx, frame, Options{SessionID: "session-a", Final: true})
+		doneA <- err
+	}()
+	go func() {
+		_, err := eng.TranscribeSegment(ctx, frame, Options{SessionID: "session-b", Final: true})
+		doneB <- err
+	}()
+
+	waitForCall := func(sessionID string) {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if inner.callCount(sessionID) >= 1 {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("session %s never reached the inner engine", sessionID)
+	}
+	waitForCall("session-a")
+	waitForCall("session-b")
+
+	inner.release("session-a")
+	inner.release("session-b")
+	if err := <-doneA; err != nil {
+		t.Fatalf("session-a: %v", err)
+	}
+	if err := <-doneB; err != nil {
+		t.Fatalf("session-b: %v", err)
+	}
+}
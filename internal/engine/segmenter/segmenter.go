@@ -0,0 +1,205 @@
+// Package segmenter buffers a PCM16 mono stream and turns it into
+// utterance-sized chunks using simple energy-based voice activity detection,
+// instead of handing the decoder a fixed-size window every time a chunk
+// arrives. Feeding whisper whole utterances (rather than arbitrary ~100ms
+// slices) gives it much more context to work with and avoids cutting words
+// mid-decode.
+package segmenter
+
+import "math"
+
+const (
+	sampleRate     = 16000
+	bytesPerSample = 2
+	frameMs        = 20 // energy is evaluated over 20ms frames
+	frameBytes     = sampleRate * bytesPerSample * frameMs / 1000
+)
+
+// Config tunes onset/offset detection.
+type Config struct {
+	// EnergyThreshold is the RMS amplitude (0..1 of full scale) above which a
+	// frame is classified as speech.
+	EnergyThreshold float64
+	// MinSpeechMs is how long energy must stay above EnergyThreshold before an
+	// utterance is considered to have started.
+	MinSpeechMs int
+	// MinSilenceMs is how long energy must stay below EnergyThreshold before
+	// an in-progress utterance is considered finished.
+	MinSilenceMs int
+	// MaxUtteranceMs forces an utterance boundary even without detected
+	// silence, so a continuous talker doesn't block finals indefinitely.
+	MaxUtteranceMs int
+}
+
+// DefaultConfig matches the thresholds described for the webrtcvad-style
+// front end: quick to trigger, patient about when to close an utterance.
+func DefaultConfig() Config {
+	return Config{
+		EnergyThreshold: 0.02,
+		MinSpeechMs:     250,
+		MinSilenceMs:    500,
+		MaxUtteranceMs:  15000,
+	}
+}
+
+type state int
+
+const (
+	stateSilence state = iota
+	stateSpeech
+)
+
+// Segmenter accumulates PCM16LE mono samples and reports utterance
+// boundaries. It is not safe for concurrent use.
+type Segmenter struct {
+	cfg Config
+
+	state        state
+	buffer       []byte // audio accumulated for the in-progress (or not yet started) utterance
+	speechMs     int    // consecutive speech time seen since entering stateSilence
+	silenceMs    int    // consecutive silence time seen since entering stateSpeech
+	utteranceMs  int    // total duration of the in-progress utterance
+	pendingFrame []byte // partial frame carried over between Push calls
+}
+
+// New constructs a Segmenter. A zero Config falls back to DefaultConfig.
+func New(cfg Config) *Segmenter {
+	if cfg.EnergyThreshold <= 0 {
+		cfg = DefaultConfig()
+	}
+	return &Segmenter{cfg: cfg}
+}
+
+// Event describes what happened to the in-progress utterance after a Push.
+type Event int
+
+const (
+	// EventNone means audio was buffered but no boundary was reached.
+	EventNone Event = iota
+	// EventPartial means an utterance is in progress and the caller may want
+	// to run a low-latency decode over Audio() for a partial transcript.
+	EventPartial
+	// EventFinal means the utterance reached a boundary (trailing silence or
+	// MaxUtteranceMs) and should be decoded as a final transcript. The
+	// Segmenter resets its buffer after returning EventFinal.
+	EventFinal
+)
+
+// Push feeds newly-arrived PCM16LE mono audio (at sampleRate) into the
+// segmenter and reports what happened to the in-progress utterance.
+func (s *Segmenter) Push(pcm []byte) Event {
+	if len(pcm) == 0 {
+		return EventNone
+	}
+
+	data := pcm
+	if len(s.pendingFrame) > 0 {
+		data = append(append([]byte(nil), s.pendingFrame...), pcm...)
+		s.pendingFrame = nil
+	}
+
+	event := EventNone
+	offset := 0
+	for offset+frameBytes <= len(data) {
+		frame := data[offset : offset+frameBytes]
+		offset += frameBytes
+
+		if isSpeech(frame, s.cfg.EnergyThreshold) {
+			s.onSpeechFrame(frame)
+		} else if ev := s.onSilenceFrame(frame); ev == EventFinal {
+			event = EventFinal
+		}
+
+		if s.state == stateSpeech && s.utteranceMs >= s.cfg.MaxUtteranceMs {
+			event = EventFinal
+			s.reset()
+		}
+	}
+
+	if offset < len(data) {
+		s.pendingFrame = append([]byte(nil), data[offset:]...)
+	}
+
+	if event == EventNone && len(s.buffer) > 0 {
+		event = EventPartial
+	}
+	return event
+}
+
+func (s *Segmenter) onSpeechFrame(frame []byte) {
+	s.silenceMs = 0
+	if s.state == stateSilence {
+		s.speechMs += frameMs
+		s.buffer = append(s.buffer, frame...)
+		if s.speechMs >= s.cfg.MinSpeechMs {
+			s.state = stateSpeech
+			s.utteranceMs = s.speechMs
+		}
+		return
+	}
+	s.buffer = append(s.buffer, frame...)
+	s.utteranceMs += frameMs
+}
+
+// onSilenceFrame returns EventFinal when trailing silence closes an
+// in-progress utterance.
+func (s *Segmenter) onSilenceFrame(frame []byte) Event {
+	if s.state == stateSilence {
+		// Silence before speech has started resets the speech-onset counter
+		// so brief noise blips don't count toward MinSpeechMs.
+		s.speechMs = 0
+		s.buffer = s.buffer[:0]
+		return EventNone
+	}
+
+	s.buffer = append(s.buffer, frame...)
+	s.utteranceMs += frameMs
+	s.silenceMs += frameMs
+	if s.silenceMs >= s.cfg.MinSilenceMs {
+		s.reset()
+		return EventFinal
+	}
+	return EventNone
+}
+
+// Audio returns the PCM accumulated for the in-progress (or just-finalised,
+// until the next Push) utterance.
+func (s *Segmenter) Audio() []byte {
+	return s.buffer
+}
+
+// Reset discards any buffered audio and returns the segmenter to its initial
+// (silence, no utterance) state. Callers should call this after consuming an
+// EventFinal's audio via Audio().
+func (s *Segmenter) reset() {
+	s.state = stateSilence
+	s.buffer = nil
+	s.speechMs = 0
+	s.silenceMs = 0
+	s.utteranceMs = 0
+}
+
+// Reset is the exported form of reset, for callers that want to abandon an
+// in-progress utterance (e.g. on stream flush) without decoding it.
+func (s *Segmenter) Reset() {
+	s.reset()
+	s.pendingFrame = nil
+}
+
+func isSpeech(frame []byte, threshold float64) bool {
+	return rmsAmplitude(frame) >= threshold
+}
+
+func rmsAmplitude(frame []byte) float64 {
+	n := len(frame) / bytesPerSample
+	if n == 0 {
+		return 0
+	}
+	var sumSquares float64
+	for i := 0; i < n; i++ {
+		sample := int16(uint16(frame[2*i]) | uint16(frame[2*i+1])<<8)
+		normalised := float64(sample) / 32768.0
+		sumSquares += normalised * normalised
+	}
+	return math.Sqrt(sumSquares / float64(n))
+}
@@ -0,0 +1,85 @@
+package segmenter
+
+import "testing"
+
+func loudFrame() []byte {
+	frame := make([]byte, frameBytes)
+	for i := 0; i < len(frame); i += 2 {
+		frame[i] = 0x00
+		frame[i+1] = 0x60 // large positive sample, well above the default threshold
+	}
+	return frame
+}
+
+func quietFrame() []byte {
+	return make([]byte, frameBytes)
+}
+
+func TestPushIgnoresBriefNoise(t *testing.T) {
+	s := New(Config{EnergyThreshold: 0.02, MinSpeechMs: 100, MinSilenceMs: 200, MaxUtteranceMs: 5000})
+
+	// One loud frame (20ms) is shorter than MinSpeechMs, so it must not
+	// surface as a final utterance once silence returns.
+	s.Push(loudFrame())
+	for i := 0; i < 10; i++ {
+		if ev := s.Push(quietFrame()); ev == EventFinal {
+			t.Fatalf("brief noise blip should not produce a final utterance")
+		}
+	}
+}
+
+func TestPushDetectsUtteranceBoundary(t *testing.T) {
+	s := New(Config{EnergyThreshold: 0.02, MinSpeechMs: 40, MinSilenceMs: 100, MaxUtteranceMs: 5000})
+
+	var lastEvent Event
+	for i := 0; i < 5; i++ {
+		lastEvent = s.Push(loudFrame())
+	}
+	if lastEvent != EventPartial {
+		t.Fatalf("expected EventPartial once MinSpeechMs is exceeded, got %v", lastEvent)
+	}
+	if len(s.Audio()) == 0 {
+		t.Fatalf("expected buffered audio for in-progress utterance")
+	}
+
+	lastEvent = EventNone
+	for i := 0; i < 5; i++ {
+		lastEvent = s.Push(quietFrame())
+	}
+	if lastEvent != EventFinal {
+		t.Fatalf("expected EventFinal once MinSilenceMs is exceeded, got %v", lastEvent)
+	}
+	if len(s.Audio()) != 0 {
+		t.Fatalf("expected buffer to be cleared after a final utterance")
+	}
+}
+
+func TestPushForcesMaxUtteranceBoundary(t *testing.T) {
+	s := New(Config{EnergyThreshold: 0.02, MinSpeechMs: 20, MinSilenceMs: 1000, MaxUtteranceMs: 100})
+
+	var event Event
+	for i := 0; i < 20 && event != EventFinal; i++ {
+		event = s.Push(loudFrame())
+	}
+	if event != EventFinal {
+		t.Fatalf("expected MaxUtteranceMs to force a final utterance, got %v", event)
+	}
+}
+
+func TestReset(t *testing.T) {
+	s := New(DefaultConfig())
+	for i := 0; i < 20; i++ {
+		s.Push(loudFrame())
+	}
+	if len(s.Audio()) == 0 {
+		t.Fatalf("expected in-progress utterance before Reset")
+	}
+
+	s.Reset()
+	if len(s.Audio()) != 0 {
+		t.Fatalf("expected Reset to discard buffered audio")
+	}
+	if s.state != stateSilence {
+		t.Fatalf("expected Reset to return to stateSilence")
+	}
+}
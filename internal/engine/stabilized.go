@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"sync"
+)
+
+// StabilizedEngine wraps an inner Engine that is being driven with
+// sliding-window audio (NativeOptions.StepMs/LengthMs/KeepMs) and runs each
+// non-final result through a per-session Stabilizer, so callers see a
+// stable, append-only prefix plus a revisable unstable tail instead of the
+// inner engine's raw (and possibly still-changing) hypothesis for every
+// window. A final result closes out its session's Stabilizer with Flush and
+// forgets that session, so StabilizedEngine's memory use tracks open
+// streams rather than growing without bound.
+type StabilizedEngine struct {
+	inner     Engine
+	threshold int
+
+	mu       sync.Mutex
+	sessions map[string]*Stabilizer
+}
+
+// NewStabilizedEngine wraps inner, confirming a sliding-window hypothesis's
+// prefix once it has agreed across threshold consecutive windows per
+// session (see Stabilizer).
+func NewStabilizedEngine(inner Engine, threshold int) *StabilizedEngine {
+	return &StabilizedEngine{
+		inner:     inner,
+		threshold: threshold,
+		sessions:  make(map[string]*Stabilizer),
+	}
+}
+
+func (e *StabilizedEngine) TranscribeSegment(ctx context.Context, audio []byte, opts Options) ([]Result, error) {
+	results, err := e.inner.TranscribeSegment(ctx, audio, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.stabilize(opts.SessionID, results), nil
+}
+
+func (e *StabilizedEngine) Flush(ctx context.Context, opts Options) ([]Result, error) {
+	results, err := e.inner.Flush(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.stabilize(opts.SessionID, results), nil
+}
+
+func (e *StabilizedEngine) Close() error {
+	return e.inner.Close()
+}
+
+// stabilize runs each result through sessionID's Stabilizer, expanding a
+// single inner result into up to two: the newly-stable prefix (Final=true)
+// and the remaining unstable tail (Final=false). A result the inner engine
+// already marked Final closes out and forgets the session instead, since
+// there is no further window to revise it against.
+func (e *StabilizedEngine) stabilize(sessionID string, results []Result) []Result {
+	if len(results) == 0 {
+		return results
+	}
+
+	out := make([]Result, 0, len(results))
+	for _, res := range results {
+		if res.Final {
+			stable := e.flushSession(sessionID)
+			if stable != nil {
+				res.Text = joinStable(stable.NewlyStable, res.Text)
+				res.StableOffset = stable.StableOffset
+			}
+			out = append(out, res)
+			continue
+		}
+
+		update := e.updateSession(sessionID, res.Text)
+		if update.NewlyStable != "" {
+			stableRes := res
+			stableRes.Final = true
+			stableRes.Text = update.NewlyStable
+			stableRes.StableOffset = update.StableOffset
+			out = append(out, stableRes)
+		}
+		unstableRes := res
+		unstableRes.Final = false
+		unstableRes.Text = update.Unstable
+		unstableRes.StableOffset = update.StableOffset
+		out = append(out, unstableRes)
+	}
+	return out
+}
+
+func (e *StabilizedEngine) updateSession(sessionID string, hypothesis string) StabilizerResult {
+	e.mu.Lock()
+	s, ok := e.sessions[sessionID]
+	if !ok {
+		s = NewStabilizer(e.threshold)
+		e.sessions[sessionID] = s
+	}
+	e.mu.Unlock()
+	return s.Update(hypothesis)
+}
+
+// flushSession confirms sessionID's remaining unstable words and forgets
+// its Stabilizer. Returns nil if the session never saw an unstable result.
+func (e *StabilizedEngine) flushSession(sessionID string) *StabilizerResult {
+	e.mu.Lock()
+	s, ok := e.sessions[sessionID]
+	delete(e.sessions, sessionID)
+	e.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	result := s.Flush()
+	return &result
+}
+
+// joinStable prefixes an inner engine's own final text with any words the
+// Stabilizer had not yet confirmed, so a final result carries the complete
+// transcript even if the last unstable tail was never re-emitted as stable.
+func joinStable(newlyStable, text string) string {
+	if newlyStable == "" {
+		return text
+	}
+	if text == "" {
+		return newlyStable
+	}
+	return newlyStable + " " + text
+}
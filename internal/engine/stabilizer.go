@@ -0,0 +1,111 @@
+package engine
+
+import "strings"
+
+// Stabilizer implements local-agreement stabilization for sliding-window
+// streaming transcription (NativeOptions.StepMs/LengthMs/KeepMs): each
+// Update call feeds it the latest hypothesis for one window, and it
+// confirms a token-wise prefix as stable once that prefix has agreed across
+// StableThreshold consecutive hypotheses. A confirmed prefix is never
+// revised again; the remaining suffix is reported unstable and may change
+// on a future Update as more audio context resolves it. This is the
+// "LocalAgreement-n" strategy used by whisper.cpp's own streaming examples,
+// applied here at the Go layer so it works the same way regardless of
+// which Engine backend is doing the decoding.
+type Stabilizer struct {
+	threshold int
+
+	history     [][]string // token-wise hypotheses for the last `threshold` windows, most recent last
+	stableWords int        // tokens already confirmed; never decreases
+}
+
+// NewStabilizer returns a Stabilizer that confirms a prefix once it has
+// agreed across threshold consecutive hypotheses. threshold < 1 is treated
+// as 1, under which every hypothesis is confirmed immediately (no
+// stabilization).
+func NewStabilizer(threshold int) *Stabilizer {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Stabilizer{threshold: threshold}
+}
+
+// StabilizerResult is one Update's or Flush's view of a sliding-window
+// hypothesis.
+type StabilizerResult struct {
+	// StableOffset is the number of leading tokens confirmed stable so far
+	// (including any newly confirmed by this call), i.e. the point in
+	// token-space a caller should treat as immutable.
+	StableOffset int
+	// NewlyStable holds only the tokens newly confirmed by this call
+	// (empty if StableOffset did not advance).
+	NewlyStable string
+	// Unstable is the remainder of the hypothesis beyond StableOffset; it
+	// may be replaced wholesale by a future call.
+	Unstable string
+}
+
+// Update feeds hypothesis, the full decoded text for the current window,
+// into the stabilizer.
+func (s *Stabilizer) Update(hypothesis string) StabilizerResult {
+	words := strings.Fields(hypothesis)
+	s.history = append(s.history, words)
+	if len(s.history) > s.threshold {
+		s.history = s.history[len(s.history)-s.threshold:]
+	}
+
+	agreed := s.stableWords
+	if len(s.history) == s.threshold {
+		if n := agreedPrefixLen(s.history); n > agreed {
+			agreed = n
+		}
+	}
+
+	var newlyStable string
+	if agreed > s.stableWords {
+		newlyStable = strings.Join(words[s.stableWords:agreed], " ")
+	}
+	s.stableWords = agreed
+
+	var unstable string
+	if agreed < len(words) {
+		unstable = strings.Join(words[agreed:], " ")
+	}
+
+	return StabilizerResult{StableOffset: agreed, NewlyStable: newlyStable, Unstable: unstable}
+}
+
+// Flush confirms every remaining word of the most recent hypothesis as
+// stable, for use once a stream ends and there are no more windows left to
+// agree across.
+func (s *Stabilizer) Flush() StabilizerResult {
+	if len(s.history) == 0 {
+		return StabilizerResult{StableOffset: s.stableWords}
+	}
+	words := s.history[len(s.history)-1]
+	var newlyStable string
+	if s.stableWords < len(words) {
+		newlyStable = strings.Join(words[s.stableWords:], " ")
+	}
+	s.stableWords = len(words)
+	return StabilizerResult{StableOffset: s.stableWords, NewlyStable: newlyStable}
+}
+
+// agreedPrefixLen returns how many leading tokens every hypothesis in hyps
+// agrees on.
+func agreedPrefixLen(hyps [][]string) int {
+	n := len(hyps[0])
+	for _, h := range hyps[1:] {
+		if len(h) < n {
+			n = len(h)
+		}
+	}
+	for i := 0; i < n; i++ {
+		for _, h := range hyps[1:] {
+			if h[i] != hyps[0][i] {
+				return i
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,71 @@
+package engine
+
+import "testing"
+
+func TestStabilizerConfirmsAgreedPrefix(t *testing.T) {
+	s := NewStabilizer(2)
+
+	first := s.Update("hello there")
+	if first.StableOffset != 0 || first.NewlyStable != "" {
+		t.Fatalf("first window should not confirm anything yet, got %+v", first)
+	}
+	if first.Unstable != "hello there" {
+		t.Fatalf("unexpected unstable text: %q", first.Unstable)
+	}
+
+	second := s.Update("hello there friend")
+	if second.StableOffset != 2 || second.NewlyStable != "hello there" {
+		t.Fatalf("expected agreed prefix to stabilize, got %+v", second)
+	}
+	if second.Unstable != "friend" {
+		t.Fatalf("unexpected unstable tail: %q", second.Unstable)
+	}
+
+	third := s.Update("hello there friend!")
+	if third.NewlyStable != "" {
+		t.Fatalf("previously stabilized words must not be re-emitted, got %+v", third)
+	}
+	if third.StableOffset != 2 {
+		t.Fatalf("stable offset must not retreat, got %d", third.StableOffset)
+	}
+}
+
+func TestStabilizerNeverRetractsStableOffset(t *testing.T) {
+	s := NewStabilizer(2)
+	s.Update("one two three")
+	s.Update("one two three")
+	afterAgreement := s.Update("one two three four")
+	if afterAgreement.StableOffset != 3 {
+		t.Fatalf("expected offset 3, got %d", afterAgreement.StableOffset)
+	}
+
+	// A later window that disagrees with an already-confirmed prefix must
+	// not walk the stable offset backwards.
+	regressed := s.Update("one wrong three four")
+	if regressed.StableOffset < 3 {
+		t.Fatalf("stable offset must never decrease, got %d", regressed.StableOffset)
+	}
+}
+
+func TestStabilizerFlushConfirmsRemainder(t *testing.T) {
+	s := NewStabilizer(3)
+	s.Update("partial")
+	result := s.Flush()
+	if result.NewlyStable != "partial" || result.StableOffset != 1 {
+		t.Fatalf("expected Flush to confirm remaining words, got %+v", result)
+	}
+
+	// Flush on an empty stabilizer (no windows seen) is a no-op.
+	empty := NewStabilizer(2)
+	if r := empty.Flush(); r.StableOffset != 0 || r.NewlyStable != "" {
+		t.Fatalf("expected no-op flush on empty stabilizer, got %+v", r)
+	}
+}
+
+func TestStabilizerThresholdBelowOneConfirmsImmediately(t *testing.T) {
+	s := NewStabilizer(0)
+	result := s.Update("immediate")
+	if result.NewlyStable != "immediate" || result.StableOffset != 1 {
+		t.Fatalf("expected threshold<1 to confirm immediately, got %+v", result)
+	}
+}
@@ -4,10 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/adapterinfo"
 )
 
+// stubWordMillis is how long each synthesized stub word "lasts", chosen to
+// be plausible speech-rate timing without depending on whisper.cpp.
+const stubWordMillis = 300
+
+// stubSpeakerTurnEvery marks a TinyDiarize-style speaker turn after every
+// Nth synthesized word, so format/integration tests can exercise
+// Word.SpeakerTurn without a real whispercpp backend.
+const stubSpeakerTurnEvery = 4
+
 // StubEngine produces deterministic transcripts without invoking Whisper.
 type StubEngine struct {
 	log          *slog.Logger
@@ -48,6 +58,7 @@ func (e *StubEngine) TranscribeSegment(ctx context.Context, audio []byte, opts O
 			Text:       text,
 			Confidence: 0.42,
 			Final:      opts.Final,
+			Words:      synthesizeWords(text, opts.UtteranceOffsetMs),
 		},
 	}, nil
 }
@@ -65,9 +76,33 @@ func (e *StubEngine) Flush(ctx context.Context, opts Options) ([]Result, error)
 			Text:       text,
 			Confidence: 1.0,
 			Final:      true,
+			Words:      synthesizeWords(text, opts.UtteranceOffsetMs),
 		},
 	}, nil
 }
 
 // SetDefaultLanguage satisfies the languageHintSetter interface; the stub ignores the hint.
 func (e *StubEngine) SetDefaultLanguage(string) {}
+
+// synthesizeWords fakes plausible per-word timing and the occasional
+// TinyDiarize-style speaker turn for text, so callers can exercise the
+// word-aligned wire format (internal/format, REST verbose_json, the gRPC
+// metadata smuggling in internal/server) without a real whispercpp backend.
+func synthesizeWords(text string, offsetMs uint64) []Word {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil
+	}
+	words := make([]Word, len(fields))
+	for i, field := range fields {
+		start := offsetMs + uint64(i)*stubWordMillis
+		words[i] = Word{
+			Text:        field,
+			StartMs:     start,
+			EndMs:       start + stubWordMillis,
+			Probability: 0.99,
+			SpeakerTurn: (i+1)%stubSpeakerTurnEvery == 0,
+		}
+	}
+	return words
+}
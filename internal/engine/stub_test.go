@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStubEngineSynthesizesWordTimings(t *testing.T) {
+	e := NewStubEngine(nil, "base")
+
+	results, err := e.TranscribeSegment(context.Background(), []byte{0, 0, 1, 0}, Options{UtteranceOffsetMs: 1000})
+	if err != nil {
+		t.Fatalf("TranscribeSegment: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+
+	words := results[0].Words
+	if len(words) == 0 {
+		t.Fatalf("expected synthesized word timings, got none")
+	}
+	if words[0].StartMs != 1000 {
+		t.Fatalf("expected first word to start at the utterance offset, got %d", words[0].StartMs)
+	}
+	for i, w := range words {
+		if w.EndMs <= w.StartMs {
+			t.Fatalf("word %d: end %d not after start %d", i, w.EndMs, w.StartMs)
+		}
+	}
+}
+
+func TestStubEngineSynthesizesSpeakerTurns(t *testing.T) {
+	words := synthesizeWords("one two three four five six seven eight", 0)
+	if len(words) != 8 {
+		t.Fatalf("expected 8 words, got %d", len(words))
+	}
+	for i, w := range words {
+		want := (i+1)%stubSpeakerTurnEvery == 0
+		if w.SpeakerTurn != want {
+			t.Fatalf("word %d (%q): SpeakerTurn = %v, want %v", i, w.Text, w.SpeakerTurn, want)
+		}
+	}
+}
+
+func TestSynthesizeWordsEmptyText(t *testing.T) {
+	if words := synthesizeWords("   ", 0); words != nil {
+		t.Fatalf("expected nil words for blank text, got %+v", words)
+	}
+}
@@ -0,0 +1,280 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// restartBackoff is the initial delay SupervisedEngine waits before
+// respawning a child that exited unexpectedly, doubling on each consecutive
+// restart (see backoffFor) so a crash-looping child backs off instead of
+// hammering the host.
+const restartBackoff = 500 * time.Millisecond
+
+// maxRestartBackoff caps backoffFor so a long-crash-looping child is still
+// retried periodically rather than being backed off indefinitely.
+const maxRestartBackoff = 30 * time.Second
+
+// healthCheckTimeout bounds how long SupervisedEngine waits for a freshly
+// spawned child to report SERVING before giving up on that spawn attempt.
+const healthCheckTimeout = 10 * time.Second
+
+// healthCheckInterval is how often SupervisedEngine polls a freshly spawned
+// child's health service while waiting for it to come up.
+const healthCheckInterval = 50 * time.Millisecond
+
+// backoffFor returns the delay before restart attempt number restarts
+// (0-indexed), doubling restartBackoff each time up to maxRestartBackoff.
+func backoffFor(restarts int) time.Duration {
+	d := restartBackoff
+	for i := 0; i < restarts && d < maxRestartBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRestartBackoff {
+		d = maxRestartBackoff
+	}
+	return d
+}
+
+// SupervisedEngine implements Engine by spawning a whisper-worker-like
+// binary as a child process and talking to it over a unix socket via
+// GRPCEngine, restarting it with backoff if it exits unexpectedly. This
+// gives Backend = "subprocess://..." the crash isolation of
+// Backend = "grpc://host:port" (a whisper.cpp crash, GPU OOM, or driver
+// hang takes down the child, not the adapter) without requiring the
+// operator to run and monitor that worker themselves. Context cancellation
+// on a TranscribeSegment/Flush call propagates to the child automatically:
+// it is forwarded over the gRPC stream like any other RemoteEngine call,
+// and the hosted NativeEngine already checks it via contextFromHandle
+// (see abort.go).
+type SupervisedEngine struct {
+	binary    string
+	options   map[string]string
+	socketDir string
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	client   *GRPCEngine
+	closed   bool
+	restarts int
+
+	// closeSignal is closed by Close to wake a handleExit goroutine that is
+	// blocked in its backoff sleep, so shutdown doesn't have to wait out a
+	// crash-looping child's delay.
+	closeSignal chan struct{}
+}
+
+// NewSupervisedEngine resolves binary (the empty string means
+// "whisper-worker", looked up on PATH), spawns it hosting an Engine over a
+// unix socket, and returns once it reports healthy. options carries
+// backend-specific configuration (accelerator, threads, word timestamps,
+// ...) as environment variable assignments forwarded to the child verbatim,
+// so NativeOptions-shaped flags stay out of the per-call Options struct and
+// every backend can define whatever keys it understands.
+func NewSupervisedEngine(binary string, options map[string]string, logger *slog.Logger) (*SupervisedEngine, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if binary == "" {
+		binary = "whisper-worker"
+	}
+	resolved, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("engine: resolve subprocess binary %q: %w", binary, err)
+	}
+	socketDir, err := os.MkdirTemp("", "whisper-worker-")
+	if err != nil {
+		return nil, fmt.Errorf("engine: create subprocess socket dir: %w", err)
+	}
+
+	s := &SupervisedEngine{
+		binary:      resolved,
+		options:     options,
+		socketDir:   socketDir,
+		logger:      logger.With("component", "engine.subprocess", "binary", resolved),
+		closeSignal: make(chan struct{}),
+	}
+	if err := s.spawn(0); err != nil {
+		os.RemoveAll(socketDir)
+		return nil, err
+	}
+	return s, nil
+}
+
+// spawn starts the child process for restartIndex (used to give it a fresh
+// socket path), dials it, and waits for it to report healthy, then installs
+// it as the active s.cmd/s.client. The dial and health check run without
+// s.mu held, so a slow or crash-looping child doesn't block currentClient
+// or Close behind them; s.mu is only taken to read/write the shared fields,
+// and spawn re-checks s.closed before installing its result in case Close
+// ran while it was in flight.
+func (s *SupervisedEngine) spawn(restartIndex int) error {
+	socketPath := filepath.Join(s.socketDir, fmt.Sprintf("engine-%d.sock", restartIndex))
+	cmd := exec.Command(s.binary)
+	cmd.Env = append(os.Environ(),
+		"NUPI_ADAPTER_LISTEN_ADDR=unix://"+socketPath,
+		"NUPI_ADAPTER_BACKEND=",
+	)
+	for key, value := range s.options {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("engine: start subprocess %s: %w", s.binary, err)
+	}
+
+	client, err := NewGRPCEngine("unix://" + socketPath)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("engine: dial subprocess socket %s: %w", socketPath, err)
+	}
+
+	if err := waitHealthy(client); err != nil {
+		_ = client.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("engine: subprocess %s did not become healthy: %w", s.binary, err)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = client.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil
+	}
+	s.cmd = cmd
+	s.client = client
+	s.mu.Unlock()
+
+	go func() {
+		waitErr := cmd.Wait()
+		s.handleExit(waitErr)
+	}()
+	return nil
+}
+
+// handleExit restarts the child after it exits, unless Close already ran.
+// It clears s.client before its backoff sleep (rather than holding s.mu
+// across the sleep and respawn) so currentClient's "subprocess is
+// restarting" fast-fail is actually reachable during that window instead of
+// callers blocking on s.mu for up to maxRestartBackoff plus
+// healthCheckTimeout, and so Close isn't blocked behind it either.
+func (s *SupervisedEngine) handleExit(waitErr error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	delay := backoffFor(s.restarts)
+	s.restarts++
+	restartIndex := s.restarts
+	s.client = nil
+	s.cmd = nil
+	s.mu.Unlock()
+
+	s.logger.Warn("subprocess exited unexpectedly; restarting", "error", waitErr, "restart_count", restartIndex, "backoff", delay)
+
+	select {
+	case <-time.After(delay):
+	case <-s.closeSignal:
+		return
+	}
+
+	if err := s.spawn(restartIndex); err != nil {
+		s.mu.Lock()
+		closed := s.closed
+		s.mu.Unlock()
+		if !closed {
+			s.logger.Error("failed to restart subprocess", "error", err)
+		}
+	}
+}
+
+// waitHealthy polls client's grpc.health.v1 service until it reports
+// SERVING or healthCheckTimeout elapses, so spawn only hands the child
+// traffic once it can actually decode.
+func waitHealthy(client *GRPCEngine) error {
+	deadline := time.Now().Add(healthCheckTimeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckInterval)
+		healthy := client.Healthy(ctx)
+		cancel()
+		if healthy {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("engine: health check did not report serving within %s", healthCheckTimeout)
+		}
+		time.Sleep(healthCheckInterval)
+	}
+}
+
+func (s *SupervisedEngine) TranscribeSegment(ctx context.Context, audio []byte, opts Options) ([]Result, error) {
+	client, err := s.currentClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.TranscribeSegment(ctx, audio, opts)
+}
+
+func (s *SupervisedEngine) Flush(ctx context.Context, opts Options) ([]Result, error) {
+	client, err := s.currentClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Flush(ctx, opts)
+}
+
+func (s *SupervisedEngine) currentClient() (*GRPCEngine, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("engine: subprocess engine is closed")
+	}
+	if s.client == nil {
+		return nil, fmt.Errorf("engine: subprocess is restarting")
+	}
+	return s.client, nil
+}
+
+// Close stops the child process and releases its socket directory. It does
+// not attempt to restart the child again even if it was mid-restart: it
+// signals closeSignal so a handleExit blocked in its backoff sleep returns
+// immediately instead of making Close wait out the remaining delay. It only
+// kills the process, not waits on it: spawn's background goroutine already
+// has a cmd.Wait() in flight for it, and os/exec does not support two
+// goroutines calling Wait on the same *exec.Cmd concurrently.
+func (s *SupervisedEngine) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	cmd, client := s.cmd, s.client
+	s.mu.Unlock()
+	close(s.closeSignal)
+
+	var err error
+	if client != nil {
+		err = client.Close()
+	}
+	if cmd != nil && cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+	if removeErr := os.RemoveAll(s.socketDir); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSupervisedEngineMissingBinaryReturnsError(t *testing.T) {
+	_, err := NewSupervisedEngine("whisper-worker-does-not-exist", nil, nil)
+	if err == nil {
+		t.Fatalf("expected error resolving a nonexistent binary")
+	}
+	if !strings.Contains(err.Error(), "whisper-worker-does-not-exist") {
+		t.Fatalf("expected error to name the binary, got: %v", err)
+	}
+}
+
+func TestSupervisedEngineHandleExitDoesNotBlockCurrentClientOrClose(t *testing.T) {
+	s := &SupervisedEngine{
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closeSignal: make(chan struct{}),
+		restarts:    20, // backoffFor(20) is already saturated at maxRestartBackoff
+	}
+
+	handleExitDone := make(chan struct{})
+	go func() {
+		s.handleExit(errors.New("boom"))
+		close(handleExitDone)
+	}()
+
+	// Give handleExit a moment to clear s.client and enter its backoff
+	// sleep before asserting currentClient fails fast instead of blocking
+	// on s.mu behind that sleep.
+	time.Sleep(20 * time.Millisecond)
+	if _, err := s.currentClient(); err == nil || !strings.Contains(err.Error(), "restarting") {
+		t.Fatalf("expected fast 'restarting' error from currentClient, got %v", err)
+	}
+
+	start := time.Now()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Close took %s, expected to return promptly instead of waiting out the backoff", elapsed)
+	}
+
+	<-handleExitDone
+}
@@ -0,0 +1,189 @@
+// Package format renders finalised engine.Result segments into the output
+// shapes clients can ask for (OpenAI's response_format on REST, or the
+// equivalent output_format stream metadata on gRPC), so both ingress paths
+// share one implementation of SRT/VTT/CSV rendering instead of duplicating
+// timestamp formatting.
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+)
+
+// Format names one of the renderings Render supports.
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	SRT  Format = "srt"
+	VTT  Format = "vtt"
+	CSV  Format = "csv"
+)
+
+// Valid reports whether f is a Format Render knows how to produce.
+func Valid(f Format) bool {
+	switch f {
+	case Text, JSON, SRT, VTT, CSV:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type Render's output should be served as.
+func ContentType(f Format) string {
+	switch f {
+	case JSON:
+		return "application/json"
+	case SRT:
+		return "application/x-subrip; charset=utf-8"
+	case VTT:
+		return "text/vtt; charset=utf-8"
+	case CSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Segment is one finalised transcript span to render, independent of
+// whichever ingress (gRPC, REST) produced it.
+type Segment struct {
+	Text    string
+	StartMs uint64
+	EndMs   uint64
+	Words   []engine.Word
+}
+
+// FromResults converts a slice of final engine.Result into Segments, for
+// callers that already have them in that shape.
+func FromResults(results []engine.Result) []Segment {
+	segments := make([]Segment, 0, len(results))
+	for _, res := range results {
+		if strings.TrimSpace(res.Text) == "" {
+			continue
+		}
+		segments = append(segments, Segment{Text: res.Text, StartMs: res.StartMs, EndMs: res.EndMs, Words: res.Words})
+	}
+	return segments
+}
+
+// Render writes segments to w in f: joined plain prose for "text", a single
+// {"text": ...} object for "json", indexed cues for "srt"/"vtt", or one row
+// per segment for "csv".
+func Render(w io.Writer, f Format, segments []Segment) error {
+	switch f {
+	case JSON:
+		return json.NewEncoder(w).Encode(struct {
+			Text  string     `json:"text"`
+			Words []jsonWord `json:"words,omitempty"`
+		}{Text: joinText(segments), Words: jsonWords(segments)})
+	case SRT:
+		for i, s := range segments {
+			if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", i+1, SRTTimestamp(s.StartMs), SRTTimestamp(s.EndMs), strings.TrimSpace(s.Text)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case VTT:
+		if _, err := io.WriteString(w, "WEBVTT\n\n"); err != nil {
+			return err
+		}
+		for i, s := range segments {
+			if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", i+1, VTTTimestamp(s.StartMs), VTTTimestamp(s.EndMs), strings.TrimSpace(s.Text)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"start_ms", "end_ms", "text"}); err != nil {
+			return err
+		}
+		for _, s := range segments {
+			if err := cw.Write([]string{fmt.Sprintf("%d", s.StartMs), fmt.Sprintf("%d", s.EndMs), strings.TrimSpace(s.Text)}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default: // Text
+		_, err := fmt.Fprintln(w, joinText(segments))
+		return err
+	}
+}
+
+// RenderString is Render for callers that want the rendering as a string
+// rather than written to an io.Writer (e.g. to set it as a gRPC transcript's
+// Text field).
+func RenderString(f Format, segments []Segment) (string, error) {
+	var b strings.Builder
+	if err := Render(&b, f, segments); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// jsonWord is the "json" format's per-word shape, carrying the same
+// word-aligned timing and TinyDiarize speaker-turn data exposed by the REST
+// verbose_json response.
+type jsonWord struct {
+	Text        string  `json:"text"`
+	StartMs     uint64  `json:"start_ms"`
+	EndMs       uint64  `json:"end_ms"`
+	Probability float32 `json:"probability"`
+	SpeakerTurn bool    `json:"speaker_turn,omitempty"`
+}
+
+// jsonWords flattens every segment's Words into one stream-ordered slice,
+// since the "json" format has no per-segment structure to nest them under.
+func jsonWords(segments []Segment) []jsonWord {
+	var out []jsonWord
+	for _, s := range segments {
+		for _, w := range s.Words {
+			out = append(out, jsonWord{
+				Text:        w.Text,
+				StartMs:     w.StartMs,
+				EndMs:       w.EndMs,
+				Probability: w.Probability,
+				SpeakerTurn: w.SpeakerTurn,
+			})
+		}
+	}
+	return out
+}
+
+func joinText(segments []Segment) string {
+	var texts []string
+	for _, s := range segments {
+		if trimmed := strings.TrimSpace(s.Text); trimmed != "" {
+			texts = append(texts, trimmed)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// SRTTimestamp renders ms as SRT's "HH:MM:SS,mmm" timestamp.
+func SRTTimestamp(ms uint64) string {
+	return timestamp(ms, ",")
+}
+
+// VTTTimestamp renders ms as WebVTT's "HH:MM:SS.mmm" timestamp.
+func VTTTimestamp(ms uint64) string {
+	return timestamp(ms, ".")
+}
+
+// timestamp renders ms as "HH:MM:SS<fractionSep>mmm" (SRT uses a comma,
+// WebVTT a period).
+func timestamp(ms uint64, fractionSep string) string {
+	hours := ms / 3_600_000
+	minutes := (ms % 3_600_000) / 60_000
+	seconds := (ms % 60_000) / 1000
+	millis := ms % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, seconds, fractionSep, millis)
+}
@@ -0,0 +1,117 @@
+package format_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/format"
+)
+
+func segments() []format.Segment {
+	return []format.Segment{
+		{Text: "hello there", StartMs: 0, EndMs: 1500},
+		{Text: "general kenobi", StartMs: 1500, EndMs: 3200},
+	}
+}
+
+func TestRenderTextJoinsSegments(t *testing.T) {
+	out, err := format.RenderString(format.Text, segments())
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+	if out != "hello there general kenobi" {
+		t.Fatalf("unexpected text output: %q", out)
+	}
+}
+
+func TestRenderJSONEscapesText(t *testing.T) {
+	out, err := format.RenderString(format.JSON, segments())
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+	if !strings.HasPrefix(out, `{"text":"hello there general kenobi"`) {
+		t.Fatalf("unexpected json output: %q", out)
+	}
+}
+
+func TestRenderJSONIncludesWordsAndSpeakerTurns(t *testing.T) {
+	segs := []format.Segment{
+		{
+			Text:    "hello there",
+			StartMs: 0,
+			EndMs:   900,
+			Words: []engine.Word{
+				{Text: "hello", StartMs: 0, EndMs: 400, Probability: 0.9},
+				{Text: "there", StartMs: 400, EndMs: 900, Probability: 0.8, SpeakerTurn: true},
+			},
+		},
+	}
+	out, err := format.RenderString(format.JSON, segs)
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+
+	var payload struct {
+		Words []struct {
+			Text        string `json:"text"`
+			SpeakerTurn bool   `json:"speaker_turn"`
+		} `json:"words"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("decode json output: %v", err)
+	}
+	if len(payload.Words) != 2 {
+		t.Fatalf("expected 2 words, got %+v", payload.Words)
+	}
+	if payload.Words[1].Text != "there" || !payload.Words[1].SpeakerTurn {
+		t.Fatalf("expected second word to carry a speaker turn, got %+v", payload.Words[1])
+	}
+}
+
+func TestRenderSRTIncludesIndexedCuesAndTimestamps(t *testing.T) {
+	out, err := format.RenderString(format.SRT, segments())
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+	if !strings.Contains(out, "1\n00:00:00,000 --> 00:00:01,500\nhello there") {
+		t.Fatalf("unexpected srt output: %q", out)
+	}
+	if !strings.Contains(out, "2\n00:00:01,500 --> 00:00:03,200\ngeneral kenobi") {
+		t.Fatalf("unexpected srt output: %q", out)
+	}
+}
+
+func TestRenderVTTStartsWithHeaderAndUsesPeriodSeparator(t *testing.T) {
+	out, err := format.RenderString(format.VTT, segments())
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+	if !strings.HasPrefix(out, "WEBVTT\n\n1\n00:00:00.000 --> 00:00:01.500") {
+		t.Fatalf("unexpected vtt output: %q", out)
+	}
+}
+
+func TestRenderCSVHasHeaderAndOneRowPerSegment(t *testing.T) {
+	out, err := format.RenderString(format.CSV, segments())
+	if err != nil {
+		t.Fatalf("RenderString: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d lines: %q", len(lines), out)
+	}
+	if lines[0] != "start_ms,end_ms,text" {
+		t.Fatalf("unexpected header: %q", lines[0])
+	}
+}
+
+func TestValidRejectsUnknownFormat(t *testing.T) {
+	if format.Valid("xml") {
+		t.Fatalf("expected xml to be invalid")
+	}
+	if !format.Valid(format.JSON) {
+		t.Fatalf("expected json to be valid")
+	}
+}
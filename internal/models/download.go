@@ -0,0 +1,142 @@
+package models
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ggufMagic is the 4-byte little-endian magic that begins every GGUF file.
+const ggufMagic = "GGUF"
+
+// ggmlMagic is the legacy magic (0x67676d6c, "ggml" little-endian) used by
+// whisper.cpp's original ggml model format.
+const ggmlMagic uint32 = 0x67676d6c
+
+// downloadResumable fetches url into dest, resuming from a partial ".part"
+// file when one already exists and the server honours range requests.
+func (m *Manager) downloadResumable(ctx context.Context, url, dest, expectedSHA256 string) error {
+	partPath := dest + ".part"
+
+	var startOffset int64
+	if info, err := os.Stat(partPath); err == nil {
+		startOffset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("models: build request: %w", err)
+	}
+	if startOffset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", startOffset))
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("models: download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// Server ignored the Range header (or there was nothing to resume);
+		// start the file over.
+		startOffset = 0
+		flags |= os.O_TRUNC
+	default:
+		return fmt.Errorf("models: download %s: unexpected status %s", url, resp.Status)
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("models: open %s: %w", partPath, err)
+	}
+
+	hasher := sha256.New()
+	if startOffset > 0 {
+		if err := rehashExisting(partPath, startOffset, hasher); err != nil {
+			out.Close()
+			return err
+		}
+	}
+
+	if _, err := io.Copy(out, io.TeeReader(resp.Body, hasher)); err != nil {
+		out.Close()
+		return fmt.Errorf("models: write %s: %w", partPath, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("models: close %s: %w", partPath, err)
+	}
+
+	if expectedSHA256 != "" {
+		sum := hex.EncodeToString(hasher.Sum(nil))
+		if sum != expectedSHA256 {
+			return fmt.Errorf("models: sha256 mismatch for %s: got %s, want %s", url, sum, expectedSHA256)
+		}
+	}
+
+	if err := os.Rename(partPath, dest); err != nil {
+		return fmt.Errorf("models: rename %s to %s: %w", partPath, dest, err)
+	}
+	return nil
+}
+
+// rehashExisting feeds the first n bytes already on disk into hasher so a
+// resumed download produces the same running digest as a full download.
+func rehashExisting(path string, n int64, hasher io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("models: reopen %s for hashing: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := io.CopyN(hasher, f, n); err != nil {
+		return fmt.Errorf("models: rehash %s: %w", path, err)
+	}
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("models: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", fmt.Errorf("models: hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// verifyMagic refuses to accept a model file whose header doesn't match the
+// GGUF or legacy GGML magic, to catch truncated downloads and HTML error
+// pages saved in place of the model.
+func verifyMagic(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("models: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return fmt.Errorf("models: read header of %s: %w", path, err)
+	}
+
+	if string(header) == ggufMagic {
+		return nil
+	}
+	if binary.LittleEndian.Uint32(header) == ggmlMagic {
+		return nil
+	}
+	return fmt.Errorf("models: %s does not look like a GGUF/GGML model (header %x)", path, header)
+}
@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Manager resolves model variants to local file paths, downloading artefacts
+// into its base directory on demand.
+type Manager struct {
+	baseDir    string
+	modelsDir  string
+	log        *slog.Logger
+	httpClient *http.Client
+}
+
+// NewManager creates a Manager rooted at baseDir, creating the models
+// subdirectory if it does not already exist.
+func NewManager(baseDir string, logger *slog.Logger) (*Manager, error) {
+	if strings.TrimSpace(baseDir) == "" {
+		return nil, fmt.Errorf("models: base dir required")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	modelsDir := filepath.Join(baseDir, "models")
+	if err := os.MkdirAll(modelsDir, 0o755); err != nil {
+		return nil, fmt.Errorf("models: create models dir: %w", err)
+	}
+
+	return &Manager{
+		baseDir:   baseDir,
+		modelsDir: modelsDir,
+		log:       logger.With("component", "models.Manager"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Minute,
+		},
+	}, nil
+}
+
+// ModelsDir returns the directory models are stored in.
+func (m *Manager) ModelsDir() string {
+	return m.modelsDir
+}
+
+// Resolve returns the local path for variant without downloading anything.
+// An explicit override takes precedence; otherwise the manifest filename for
+// variant is looked up within the models directory.
+func (m *Manager) Resolve(variant, override string) (string, error) {
+	if strings.TrimSpace(override) != "" {
+		if _, err := os.Stat(override); err != nil {
+			return "", fmt.Errorf("models: override path %q: %w", override, err)
+		}
+		return override, nil
+	}
+
+	manifest, err := DefaultManifest()
+	if err != nil {
+		return "", err
+	}
+	v, ok := manifest.Variants[variant]
+	if !ok {
+		return "", fmt.Errorf("models: unknown variant %q", variant)
+	}
+	path := filepath.Join(m.modelsDir, v.Filename)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("models: variant %q not present at %s: %w", variant, path, err)
+	}
+	return path, nil
+}
+
+// EnsureOptions configures how EnsureVariant resolves and, if necessary,
+// downloads a variant.
+type EnsureOptions struct {
+	// Manifest supplies the variant definitions; callers typically pass
+	// models.DefaultManifest().
+	Manifest Manifest
+	// Override, when set, is returned as-is once its existence is verified,
+	// bypassing the manifest and any download.
+	Override string
+	// Accelerator selects a companion artefact to fetch alongside the main
+	// model, when the variant declares one: "coreml" ensures Variant.CoreMLURL
+	// is downloaded, "openvino" ensures Variant.OpenVINOURL is. These mirror
+	// config.AcceleratorCoreML / config.AcceleratorOpenVINO; other values are
+	// ignored since they need no sidecar.
+	Accelerator string
+}
+
+// EnsureVariant returns the local path for variant, downloading it from the
+// manifest's mirrors if the file is not already present (or fails
+// verification). It refuses to hand back a model whose header magic doesn't
+// look like a GGUF/GGML file.
+func (m *Manager) EnsureVariant(ctx context.Context, variant string, opts EnsureOptions) (string, error) {
+	if strings.TrimSpace(opts.Override) != "" {
+		if _, err := os.Stat(opts.Override); err != nil {
+			return "", fmt.Errorf("models: override path %q: %w", opts.Override, err)
+		}
+		return opts.Override, nil
+	}
+
+	v, ok := opts.Manifest.Variants[variant]
+	if !ok {
+		return "", fmt.Errorf("models: unknown variant %q", variant)
+	}
+	if strings.TrimSpace(v.Filename) == "" {
+		return "", fmt.Errorf("models: variant %q has no filename", variant)
+	}
+
+	dest := filepath.Join(m.modelsDir, v.Filename)
+
+	if info, err := os.Stat(dest); err == nil && info.Size() > 0 {
+		if verr := verifyVariant(dest, v); verr == nil {
+			return dest, m.ensureSidecar(ctx, variant, v, opts.Accelerator)
+		}
+		m.log.Warn("existing model failed verification; re-downloading", "variant", variant, "path", dest)
+	}
+
+	mirrors := v.Mirrors()
+	if len(mirrors) == 0 {
+		return "", fmt.Errorf("models: variant %q has no download URLs", variant)
+	}
+
+	var lastErr error
+	for _, mirror := range mirrors {
+		m.log.Info("downloading model", "variant", variant, "url", mirror, "dest", dest)
+		if err := m.downloadResumable(ctx, mirror, dest, v.SHA256); err != nil {
+			m.log.Warn("mirror failed; trying next", "variant", variant, "url", mirror, "error", err)
+			lastErr = err
+			continue
+		}
+		if err := verifyVariant(dest, v); err != nil {
+			lastErr = err
+			continue
+		}
+		return dest, m.ensureSidecar(ctx, variant, v, opts.Accelerator)
+	}
+	return "", fmt.Errorf("models: all mirrors failed for variant %q: %w", variant, lastErr)
+}
+
+// ensureSidecar downloads the companion artefact matching accelerator, when
+// the variant declares one. It is a no-op for accelerators that don't need a
+// sidecar (e.g. "cpu", "metal", "cuda", "auto").
+func (m *Manager) ensureSidecar(ctx context.Context, variant string, v Variant, accelerator string) error {
+	var sidecarURL string
+	switch accelerator {
+	case "coreml":
+		sidecarURL = v.CoreMLURL
+	case "openvino":
+		sidecarURL = v.OpenVINOURL
+	default:
+		return nil
+	}
+	if strings.TrimSpace(sidecarURL) == "" {
+		return fmt.Errorf("models: variant %q has no %s sidecar", variant, accelerator)
+	}
+
+	dest := filepath.Join(m.modelsDir, sidecarFilename(sidecarURL))
+	if info, err := os.Stat(dest); err == nil && info.Size() > 0 {
+		return nil
+	}
+
+	m.log.Info("downloading accelerator sidecar", "variant", variant, "accelerator", accelerator, "url", sidecarURL, "dest", dest)
+	if err := m.downloadResumable(ctx, sidecarURL, dest, ""); err != nil {
+		return fmt.Errorf("models: download %s sidecar for %q: %w", accelerator, variant, err)
+	}
+	return nil
+}
+
+// sidecarFilename derives a local filename for a sidecar URL from its final
+// path segment, falling back to a generic name if the URL has none.
+func sidecarFilename(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if base := filepath.Base(parsed.Path); base != "." && base != "/" && base != "" {
+			return base
+		}
+	}
+	return "sidecar.bin"
+}
+
+func verifyVariant(path string, v Variant) error {
+	if err := verifyMagic(path); err != nil {
+		return err
+	}
+	if strings.TrimSpace(v.SHA256) == "" {
+		return nil
+	}
+	sum, err := sha256File(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sum, v.SHA256) {
+		return fmt.Errorf("models: sha256 mismatch for %s: got %s, want %s", path, sum, v.SHA256)
+	}
+	return nil
+}
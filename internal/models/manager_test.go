@@ -0,0 +1,200 @@
+package models
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManagerResolveWithOverride(t *testing.T) {
+	tempDir := t.TempDir()
+	manager, err := NewManager(tempDir, nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+
+	override := filepath.Join(tempDir, "custom.bin")
+	if err := os.WriteFile(override, []byte("GGUF"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	path, err := manager.Resolve("base", override)
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if path != override {
+		t.Fatalf("Resolve() = %q, want %q", path, override)
+	}
+}
+
+func TestManagerResolveUnknownVariant(t *testing.T) {
+	manager, err := NewManager(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+	if _, err := manager.Resolve("does-not-exist", ""); err == nil {
+		t.Fatalf("expected error for unknown variant")
+	}
+}
+
+func TestEnsureVariantReusesExistingFile(t *testing.T) {
+	tempDir := t.TempDir()
+	manager, err := NewManager(tempDir, nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+
+	manifest := Manifest{Variants: map[string]Variant{
+		"base": {Filename: "ggml-base.en.bin"},
+	}}
+	dest := filepath.Join(manager.ModelsDir(), "ggml-base.en.bin")
+	if err := os.WriteFile(dest, []byte("GGUF"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	path, err := manager.EnsureVariant(context.Background(), "base", EnsureOptions{Manifest: manifest})
+	if err != nil {
+		t.Fatalf("EnsureVariant() error: %v", err)
+	}
+	if path != dest {
+		t.Fatalf("EnsureVariant() = %q, want %q", path, dest)
+	}
+}
+
+func TestEnsureVariantDownloadsFromMirror(t *testing.T) {
+	payload := []byte("GGUF" + "rest-of-the-model-bytes")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	tempDir := t.TempDir()
+	manager, err := NewManager(tempDir, nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+
+	manifest := Manifest{Variants: map[string]Variant{
+		"base": {Filename: "ggml-base.en.bin", URL: srv.URL},
+	}}
+
+	path, err := manager.EnsureVariant(context.Background(), "base", EnsureOptions{Manifest: manifest})
+	if err != nil {
+		t.Fatalf("EnsureVariant() error: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Fatalf("downloaded content mismatch")
+	}
+}
+
+func TestEnsureVariantFallsBackToNextMirror(t *testing.T) {
+	payload := []byte("GGUF" + "good-mirror-bytes")
+	badSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer badSrv.Close()
+	goodSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer goodSrv.Close()
+
+	manager, err := NewManager(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+
+	manifest := Manifest{Variants: map[string]Variant{
+		"base": {Filename: "ggml-base.en.bin", URL: badSrv.URL, URLs: []string{goodSrv.URL}},
+	}}
+
+	path, err := manager.EnsureVariant(context.Background(), "base", EnsureOptions{Manifest: manifest})
+	if err != nil {
+		t.Fatalf("EnsureVariant() error: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Fatalf("downloaded content mismatch")
+	}
+}
+
+func TestEnsureVariantDownloadsAcceleratorSidecar(t *testing.T) {
+	modelPayload := []byte("GGUF" + "model-bytes")
+	sidecarPayload := []byte("coreml-sidecar-bytes")
+
+	modelSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(modelPayload)
+	}))
+	defer modelSrv.Close()
+	sidecarSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(sidecarPayload)
+	}))
+	defer sidecarSrv.Close()
+
+	manager, err := NewManager(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+
+	manifest := Manifest{Variants: map[string]Variant{
+		"base": {
+			Filename:  "ggml-base.en.bin",
+			URL:       modelSrv.URL,
+			CoreMLURL: sidecarSrv.URL + "/ggml-base.en-encoder.mlmodelc.zip",
+		},
+	}}
+
+	_, err = manager.EnsureVariant(context.Background(), "base", EnsureOptions{Manifest: manifest, Accelerator: "coreml"})
+	if err != nil {
+		t.Fatalf("EnsureVariant() error: %v", err)
+	}
+
+	sidecarPath := filepath.Join(manager.ModelsDir(), "ggml-base.en-encoder.mlmodelc.zip")
+	got, err := os.ReadFile(sidecarPath)
+	if err != nil {
+		t.Fatalf("ReadFile(sidecar) error: %v", err)
+	}
+	if string(got) != string(sidecarPayload) {
+		t.Fatalf("sidecar content mismatch")
+	}
+}
+
+func TestEnsureVariantFailsWhenSidecarMissing(t *testing.T) {
+	payload := []byte("GGUF" + "model-bytes")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	manager, err := NewManager(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("NewManager() error: %v", err)
+	}
+
+	manifest := Manifest{Variants: map[string]Variant{
+		"base": {Filename: "ggml-base.en.bin", URL: srv.URL},
+	}}
+
+	if _, err := manager.EnsureVariant(context.Background(), "base", EnsureOptions{Manifest: manifest, Accelerator: "openvino"}); err == nil {
+		t.Fatalf("expected error when variant declares no openvino sidecar")
+	}
+}
+
+func TestVerifyMagicRejectsNonModelFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-model.bin")
+	if err := os.WriteFile(path, []byte("<html>404</html>"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+	if err := verifyMagic(path); err == nil {
+		t.Fatalf("expected verifyMagic to reject non-model content")
+	}
+}
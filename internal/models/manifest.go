@@ -0,0 +1,76 @@
+package models
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//go:embed embedded_manifest.json
+var embeddedManifest []byte
+
+// Variant describes a single downloadable model artefact.
+type Variant struct {
+	DisplayName string `json:"display_name"`
+	Filename    string `json:"filename"`
+	// URL is the primary download location, kept for backward compatibility
+	// with manifests that only ever had one source.
+	URL string `json:"url,omitempty"`
+	// URLs lists additional mirrors tried, in order, after URL.
+	URLs []string `json:"urls,omitempty"`
+	// Quantization identifies the GGML/GGUF quantisation scheme (e.g. q5_0, q8_0, f16).
+	Quantization string `json:"quantization,omitempty"`
+	SHA256       string `json:"sha256,omitempty"`
+	SizeBytes    int64  `json:"size_bytes,omitempty"`
+	// CoreMLURL and OpenVINOURL point at optional companion acceleration artefacts.
+	CoreMLURL   string `json:"coreml_url,omitempty"`
+	OpenVINOURL string `json:"openvino_url,omitempty"`
+}
+
+// Mirrors returns every download location for the variant, primary first,
+// with blanks and duplicates removed.
+func (v Variant) Mirrors() []string {
+	seen := make(map[string]struct{}, len(v.URLs)+1)
+	mirrors := make([]string, 0, len(v.URLs)+1)
+	add := func(u string) {
+		u = strings.TrimSpace(u)
+		if u == "" {
+			return
+		}
+		if _, ok := seen[u]; ok {
+			return
+		}
+		seen[u] = struct{}{}
+		mirrors = append(mirrors, u)
+	}
+	add(v.URL)
+	for _, u := range v.URLs {
+		add(u)
+	}
+	return mirrors
+}
+
+// Manifest lists every model variant known to the adapter.
+type Manifest struct {
+	Variants map[string]Variant `json:"variants"`
+}
+
+// LoadManifest decodes a manifest document from r.
+func LoadManifest(r io.Reader) (Manifest, error) {
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return Manifest{}, fmt.Errorf("models: decode manifest: %w", err)
+	}
+	if manifest.Variants == nil {
+		manifest.Variants = map[string]Variant{}
+	}
+	return manifest, nil
+}
+
+// DefaultManifest returns the manifest embedded in the binary at build time.
+func DefaultManifest() (Manifest, error) {
+	return LoadManifest(bytes.NewReader(embeddedManifest))
+}
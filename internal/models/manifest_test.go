@@ -0,0 +1,52 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultManifestHasVariants(t *testing.T) {
+	manifest, err := DefaultManifest()
+	if err != nil {
+		t.Fatalf("DefaultManifest() error: %v", err)
+	}
+	if len(manifest.Variants) == 0 {
+		t.Fatalf("expected at least one variant in the embedded manifest")
+	}
+	base, ok := manifest.Variants["base"]
+	if !ok {
+		t.Fatalf("expected a %q variant", "base")
+	}
+	if base.Filename == "" {
+		t.Fatalf("expected base variant to declare a filename")
+	}
+}
+
+func TestLoadManifestRejectsInvalidJSON(t *testing.T) {
+	if _, err := LoadManifest(strings.NewReader("not json")); err == nil {
+		t.Fatalf("expected error decoding invalid manifest")
+	}
+}
+
+func TestVariantMirrorsDedupsAndOrders(t *testing.T) {
+	v := Variant{
+		URL:  "https://primary.example/model.bin",
+		URLs: []string{"https://primary.example/model.bin", "https://mirror.example/model.bin", ""},
+	}
+	mirrors := v.Mirrors()
+	want := []string{"https://primary.example/model.bin", "https://mirror.example/model.bin"}
+	if len(mirrors) != len(want) {
+		t.Fatalf("expected %d mirrors, got %d (%v)", len(want), len(mirrors), mirrors)
+	}
+	for i, m := range want {
+		if mirrors[i] != m {
+			t.Fatalf("mirror[%d]: got %q, want %q", i, mirrors[i], m)
+		}
+	}
+}
+
+func TestVariantMirrorsEmpty(t *testing.T) {
+	if mirrors := (Variant{}).Mirrors(); len(mirrors) != 0 {
+		t.Fatalf("expected no mirrors, got %v", mirrors)
+	}
+}
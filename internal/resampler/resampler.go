@@ -0,0 +1,64 @@
+// Package resampler converts PCM16 mono audio between sample rates using
+// linear interpolation. It exists so ingress paths that receive audio at a
+// rate other than the engine's fixed 16kHz (e.g. WebRTC/Opus, which decodes
+// to 48kHz) can resample in-process instead of requiring a client-side
+// transcoder.
+package resampler
+
+// Resample linearly interpolates pcm (16-bit little-endian mono samples at
+// fromHz) to toHz. It returns pcm unchanged when fromHz equals toHz, and nil
+// for an empty or single-sample input.
+func Resample(pcm []int16, fromHz, toHz int) []int16 {
+	if fromHz == toHz || len(pcm) == 0 {
+		return pcm
+	}
+	if len(pcm) == 1 {
+		return pcm
+	}
+
+	outLen := (len(pcm)-1)*toHz/fromHz + 1
+	if outLen < 1 {
+		return nil
+	}
+	out := make([]int16, outLen)
+	for i := range out {
+		// srcPos is this output sample's fractional position in the input,
+		// scaled by fromHz/toHz.
+		srcPos := float64(i) * float64(fromHz) / float64(toHz)
+		lo := int(srcPos)
+		if lo >= len(pcm)-1 {
+			out[i] = pcm[len(pcm)-1]
+			continue
+		}
+		frac := srcPos - float64(lo)
+		out[i] = int16(float64(pcm[lo])*(1-frac) + float64(pcm[lo+1])*frac)
+	}
+	return out
+}
+
+// ResampleBytes is Resample's counterpart for little-endian PCM16 byte
+// slices, the wire format Engine.TranscribeSegment expects.
+func ResampleBytes(pcm []byte, fromHz, toHz int) []byte {
+	samples := BytesToInt16(pcm)
+	resampled := Resample(samples, fromHz, toHz)
+	return Int16ToBytes(resampled)
+}
+
+// BytesToInt16 decodes little-endian PCM16 bytes into samples.
+func BytesToInt16(pcm []byte) []int16 {
+	samples := make([]int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int16(uint16(pcm[2*i]) | uint16(pcm[2*i+1])<<8)
+	}
+	return samples
+}
+
+// Int16ToBytes encodes samples as little-endian PCM16 bytes.
+func Int16ToBytes(samples []int16) []byte {
+	out := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		out[2*i] = byte(uint16(s))
+		out[2*i+1] = byte(uint16(s) >> 8)
+	}
+	return out
+}
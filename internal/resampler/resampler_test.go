@@ -0,0 +1,56 @@
+package resampler
+
+import "testing"
+
+func TestResampleSameRateReturnsInputUnchanged(t *testing.T) {
+	pcm := []int16{1, 2, 3, 4}
+	out := Resample(pcm, 16000, 16000)
+	if len(out) != len(pcm) {
+		t.Fatalf("expected %d samples, got %d", len(pcm), len(out))
+	}
+	for i := range pcm {
+		if out[i] != pcm[i] {
+			t.Fatalf("sample %d: expected %d, got %d", i, pcm[i], out[i])
+		}
+	}
+}
+
+func TestResampleEmptyAndSingleSample(t *testing.T) {
+	if out := Resample(nil, 48000, 16000); out != nil {
+		t.Fatalf("expected nil for empty input, got %v", out)
+	}
+	single := []int16{42}
+	if out := Resample(single, 48000, 16000); len(out) != 1 || out[0] != 42 {
+		t.Fatalf("expected single sample passed through unchanged, got %v", out)
+	}
+}
+
+func TestResampleDownsamplesWebRTCRateToEngineRate(t *testing.T) {
+	// 48kHz -> 16kHz is the conversion the WebRTC/Opus ingress needs before
+	// handing samples to Engine.TranscribeSegment.
+	pcm := make([]int16, 480) // 10ms at 48kHz
+	for i := range pcm {
+		pcm[i] = int16(i)
+	}
+	out := Resample(pcm, 48000, 16000)
+	wantLen := (len(pcm)-1)*16000/48000 + 1
+	if len(out) != wantLen {
+		t.Fatalf("expected %d samples, got %d", wantLen, len(out))
+	}
+	if out[0] != pcm[0] {
+		t.Fatalf("expected first sample to be preserved, got %d", out[0])
+	}
+}
+
+func TestResampleBytesRoundTripsLittleEndianPCM16(t *testing.T) {
+	pcm := []byte{0x01, 0x00, 0xFF, 0x7F} // samples 1, 32767
+	out := ResampleBytes(pcm, 16000, 16000)
+	if len(out) != len(pcm) {
+		t.Fatalf("expected %d bytes, got %d", len(pcm), len(out))
+	}
+	for i := range pcm {
+		if out[i] != pcm[i] {
+			t.Fatalf("byte %d: expected %#x, got %#x", i, pcm[i], out[i])
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	napv1 "github.com/nupi-ai/nupi/api/nap/v1"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/server"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// blockingEngine holds every TranscribeSegment call open until released is
+// closed, so a test can observe whether the server lets distinct sessions'
+// calls run concurrently rather than serialising on a shared lock.
+type blockingEngine struct {
+	released    chan struct{}
+	inFlight    int32
+	maxInFlight int32
+}
+
+func newBlockingEngine() *blockingEngine {
+	return &blockingEngine{released: make(chan struct{})}
+}
+
+func (e *blockingEngine) TranscribeSegment(ctx context.Context, audio []byte, opts engine.Options) ([]engine.Result, error) {
+	n := atomic.AddInt32(&e.inFlight, 1)
+	for {
+		old := atomic.LoadInt32(&e.maxInFlight)
+		if n <= old || atomic.CompareAndSwapInt32(&e.maxInFlight, old, n) {
+			break
+		}
+	}
+	defer atomic.AddInt32(&e.inFlight, -1)
+
+	select {
+	case <-e.released:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return []engine.Result{{Text: "session:" + opts.SessionID, Final: false}}, nil
+}
+
+func (e *blockingEngine) Flush(ctx context.Context, opts engine.Options) ([]engine.Result, error) {
+	return []engine.Result{{Text: "flush:" + opts.SessionID, Final: true}}, nil
+}
+
+func (e *blockingEngine) Close() error { return nil }
+
+// TestStreamTranscriptionConcurrentSessionsDoNotSerialize starts two
+// concurrent StreamTranscription RPCs with distinct session IDs against a
+// blockingEngine and asserts the server lets both TranscribeSegment calls be
+// in flight at once, rather than queuing the second behind the first.
+func TestStreamTranscriptionConcurrentSessionsDoNotSerialize(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lis := bufconn.Listen(bufSize)
+	defer lis.Close()
+
+	grpcServer := grpc.NewServer()
+	t.Cleanup(grpcServer.Stop)
+
+	cfg := config.Config{ListenAddr: "bufconn", ModelVariant: "small", Language: "en", LogLevel: "debug"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	eng := newBlockingEngine()
+	recorder := telemetry.NewRecorder(logger)
+	napv1.RegisterSpeechToTextServiceServer(grpcServer, server.New(cfg, logger, eng, recorder))
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil &&
+			!errors.Is(err, grpc.ErrServerStopped) &&
+			!errors.Is(err, net.ErrClosed) &&
+			err.Error() != "closed" {
+			t.Errorf("Serve() error: %v", err)
+		}
+	}()
+
+	dial := func() *grpc.ClientConn {
+		conn, err := grpc.DialContext(ctx, "bufconn",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			t.Fatalf("DialContext error: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	openStream := func(sessionID string) napv1.SpeechToTextService_StreamTranscriptionClient {
+		client := napv1.NewSpeechToTextServiceClient(dial())
+		stream, err := client.StreamTranscription(ctx)
+		if err != nil {
+			t.Fatalf("StreamTranscription error: %v", err)
+		}
+		if err := stream.Send(&napv1.StreamTranscriptionRequest{
+			SessionId: sessionID,
+			StreamId:  "mic",
+			Segment:   &napv1.Segment{Sequence: 1, Audio: []byte("abcd")},
+		}); err != nil {
+			t.Fatalf("Send segment error: %v", err)
+		}
+		return stream
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); openStream("session-a") }()
+	go func() { defer wg.Done(); openStream("session-b") }()
+	wg.Wait()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&eng.maxInFlight) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := atomic.LoadInt32(&eng.maxInFlight); got < 2 {
+		t.Fatalf("expected two concurrent sessions to be in flight together, max observed = %d", got)
+	}
+
+	close(eng.released)
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// InstanceInfo describes one alias cmd/adapter fanned NUPI_ADAPTER_CONFIG out
+// into, for the /v1/instances endpoint. A single-instance adapter still
+// reports itself as a one-element list with an empty Alias.
+type InstanceInfo struct {
+	Alias        string `json:"alias"`
+	ListenAddr   string `json:"listen_addr"`
+	RESTAddr     string `json:"rest_addr"`
+	ModelVariant string `json:"model_variant"`
+	Language     string `json:"language"`
+}
+
+// NewInstancesHandler serves the full list of instances sharing this process
+// as JSON, so an operator can discover every alias's gRPC address (and which
+// ones are reachable) from any one of their REST endpoints.
+func NewInstancesHandler(instances []InstanceInfo) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(instances)
+	})
+}
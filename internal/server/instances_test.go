@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInstancesHandlerServesJSON(t *testing.T) {
+	handler := NewInstancesHandler([]InstanceInfo{
+		{Alias: "en", ListenAddr: "127.0.0.1:50061", ModelVariant: "tiny.en", Language: "en"},
+		{Alias: "pl", ListenAddr: "127.0.0.1:50062", ModelVariant: "large-v3", Language: "pl"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/instances", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got []InstanceInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(got) != 2 || got[0].Alias != "en" || got[1].Alias != "pl" {
+		t.Fatalf("unexpected instances: %+v", got)
+	}
+}
+
+func TestInstancesHandlerRejectsNonGet(t *testing.T) {
+	handler := NewInstancesHandler(nil)
+	req := httptest.NewRequest(http.MethodPost, "/v1/instances", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
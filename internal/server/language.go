@@ -0,0 +1,30 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+)
+
+// isoLanguageMetadataKey is the stream/request metadata key clients set with
+// their own detected BCP-47/ISO-639-1 language tag (for example, the
+// language reported by a browser's SpeechRecognition API), consulted when
+// the caller asks for "client"-resolved language.
+const isoLanguageMetadataKey = "nupi.lang.iso1"
+
+// resolveLanguage decides which language hint to pass to the engine.
+// clientValue is whatever the caller requested (a stream's configured
+// language, or the REST request's "language" field): "auto" and any
+// specific BCP-47/ISO-639-1 tag are passed straight through. "client" asks
+// the server to trust the caller's own detection instead, carried in meta
+// under isoLanguageMetadataKey; if that hint is missing or blank, it falls
+// back to DefaultLanguage rather than guessing.
+func resolveLanguage(clientValue string, meta map[string]string) string {
+	if clientValue != "client" {
+		return clientValue
+	}
+	if hint := strings.TrimSpace(meta[isoLanguageMetadataKey]); hint != "" {
+		return hint
+	}
+	return config.DefaultLanguage
+}
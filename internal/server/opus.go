@@ -0,0 +1,29 @@
+package server
+
+import "errors"
+
+// opusSampleRate is the clock rate Opus-in-WebRTC and Opus-in-WebSocket
+// payloads are always sent at (RFC 7587), independent of the input signal's
+// actual bandwidth.
+const opusSampleRate = 48000
+
+// maxOpusFrameSamples bounds a single decoded Opus frame: Opus caps frames
+// at 120ms, which at opusSampleRate mono is 5760 samples.
+const maxOpusFrameSamples = 5760
+
+// ErrOpusUnavailable is returned by newOpusDecoder when the adapter was
+// built without the opus build tag (see opus_native.go/opus_stub.go), so the
+// WebRTC/WebSocket ingress can still compile and report a clear error
+// instead of refusing to build on hosts without libopus.
+var ErrOpusUnavailable = errors.New("server: opus decoding unavailable, build with -tags opus")
+
+// opusDecoder decodes Opus packets into 16-bit mono PCM samples at
+// opusSampleRate. Its only implementation (opus_native.go) binds libopus via
+// github.com/hraban/opus; it is an interface so tests can supply a fake
+// decoder without linking libopus.
+type opusDecoder interface {
+	// Decode decodes one Opus packet into pcm, returning the number of
+	// samples written.
+	Decode(packet []byte, pcm []int16) (int, error)
+	Close() error
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/resampler"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// opusChunkMs is how much resampled audio opusSession buffers before calling
+// Engine.TranscribeSegment, mirroring restChunkMs's role for REST uploads.
+const opusChunkMs = 1000
+
+// opusBytesPerMs is the byte rate of 16-bit mono PCM at the engine's fixed
+// 16kHz, used to size chunks and track stream-absolute timestamps.
+const opusBytesPerMs = 2 * 16000 / 1000
+
+// opusSession decodes a sequence of Opus packets from one WebRTC track or
+// WebSocket connection into the engine's 16kHz PCM16 format and feeds them
+// through the same TranscribeSegment/Flush pipeline StreamTranscription and
+// TranscriptionHandler use, so every ingress shares one transcription
+// contract.
+type opusSession struct {
+	log      *slog.Logger
+	engine   engine.Engine
+	metrics  *telemetry.StreamMetrics
+	language string
+
+	decoder opusDecoder
+	pcmBuf  []byte
+
+	elapsedMs uint64
+	sequence  uint64
+}
+
+// newOpusSession constructs an opusSession. metrics may be nil in tests.
+func newOpusSession(logger *slog.Logger, eng engine.Engine, metrics *telemetry.StreamMetrics, language string) (*opusSession, error) {
+	decoder, err := newOpusDecoder(opusSampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("opus session: %w", err)
+	}
+	return &opusSession{
+		log:      logger,
+		engine:   eng,
+		metrics:  metrics,
+		language: language,
+		decoder:  decoder,
+	}, nil
+}
+
+// ingest decodes one Opus packet, resamples it to the engine's 16kHz, and
+// forwards any full opusChunkMs chunks to the engine.
+func (s *opusSession) ingest(ctx context.Context, packet []byte) error {
+	pcm := make([]int16, maxOpusFrameSamples)
+	n, err := s.decoder.Decode(packet, pcm)
+	if err != nil {
+		return fmt.Errorf("decode opus packet: %w", err)
+	}
+
+	resampled := resampler.Resample(pcm[:n], opusSampleRate, 16000)
+	s.pcmBuf = append(s.pcmBuf, resampler.Int16ToBytes(resampled)...)
+
+	chunkBytes := opusChunkMs * opusBytesPerMs
+	for len(s.pcmBuf) >= chunkBytes {
+		if err := s.transcribeChunk(ctx, s.pcmBuf[:chunkBytes], false); err != nil {
+			return err
+		}
+		s.pcmBuf = s.pcmBuf[chunkBytes:]
+	}
+	return nil
+}
+
+// finish flushes any buffered audio as a final segment and closes out the
+// session, mirroring TranscriptionHandler.transcribe's trailing Flush call.
+func (s *opusSession) finish(ctx context.Context) ([]engine.Result, error) {
+	defer s.decoder.Close()
+
+	var results []engine.Result
+	if len(s.pcmBuf) > 0 {
+		res, err := s.transcribeChunkResults(ctx, s.pcmBuf, false)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res...)
+		s.pcmBuf = nil
+	}
+
+	if s.metrics != nil {
+		s.metrics.RecordFlush()
+	}
+	final, err := s.engine.Flush(ctx, engine.Options{Language: s.language, Final: true, Sequence: s.sequence})
+	if err != nil {
+		return nil, fmt.Errorf("flush: %w", err)
+	}
+	for _, res := range final {
+		if s.metrics != nil {
+			s.metrics.RecordTranscript(s.sequence, res.Text, res.Final)
+		}
+	}
+	return append(results, final...), nil
+}
+
+func (s *opusSession) transcribeChunk(ctx context.Context, chunk []byte, final bool) error {
+	_, err := s.transcribeChunkResults(ctx, chunk, final)
+	return err
+}
+
+func (s *opusSession) transcribeChunkResults(ctx context.Context, chunk []byte, final bool) ([]engine.Result, error) {
+	s.sequence++
+	durationMs := uint64(len(chunk)) / opusBytesPerMs
+	if s.metrics != nil {
+		s.metrics.RecordSegment(s.sequence, len(chunk), final)
+	}
+	results, err := s.engine.TranscribeSegment(ctx, chunk, engine.Options{
+		Language:          s.language,
+		Sequence:          s.sequence,
+		Final:             final,
+		UtteranceOffsetMs: s.elapsedMs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("transcribe segment %d: %w", s.sequence, err)
+	}
+	for _, res := range results {
+		if s.metrics != nil {
+			s.metrics.RecordTranscript(s.sequence, res.Text, res.Final)
+		}
+	}
+	s.elapsedMs += durationMs
+	return results, nil
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+)
+
+// fakeOpusDecoder "decodes" a packet by treating its bytes as already-PCM16
+// samples, so tests can exercise opusSession's chunking and engine feed
+// without linking libopus.
+type fakeOpusDecoder struct {
+	closed bool
+}
+
+func (d *fakeOpusDecoder) Decode(packet []byte, pcm []int16) (int, error) {
+	n := len(packet) / 2
+	for i := 0; i < n; i++ {
+		pcm[i] = int16(uint16(packet[2*i]) | uint16(packet[2*i+1])<<8)
+	}
+	return n, nil
+}
+
+func (d *fakeOpusDecoder) Close() error {
+	d.closed = true
+	return nil
+}
+
+func newTestOpusSession(eng engine.Engine) *opusSession {
+	return &opusSession{
+		log:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		engine:   eng,
+		language: "en",
+		decoder:  &fakeOpusDecoder{},
+	}
+}
+
+func TestOpusSessionBuffersUntilChunkBoundary(t *testing.T) {
+	eng := engine.NewStubEngine(slog.New(slog.NewTextHandler(io.Discard, nil)), "tiny")
+	session := newTestOpusSession(eng)
+
+	// A single 10ms "packet" at opusSampleRate is well short of
+	// opusChunkMs, so ingest must buffer it rather than calling the engine.
+	packet := make([]byte, 2*480)
+	if err := session.ingest(context.Background(), packet); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if session.sequence != 0 {
+		t.Fatalf("expected no engine call yet, sequence = %d", session.sequence)
+	}
+	if len(session.pcmBuf) == 0 {
+		t.Fatalf("expected decoded audio to be buffered")
+	}
+}
+
+func TestOpusSessionFinishFlushesBufferedAudio(t *testing.T) {
+	eng := engine.NewStubEngine(slog.New(slog.NewTextHandler(io.Discard, nil)), "tiny")
+	session := newTestOpusSession(eng)
+
+	packet := make([]byte, 2*480)
+	if err := session.ingest(context.Background(), packet); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	results, err := session.finish(context.Background())
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatalf("expected finish to flush buffered audio through the engine")
+	}
+	if session.pcmBuf != nil {
+		t.Fatalf("expected buffer to be cleared after finish")
+	}
+	if !session.decoder.(*fakeOpusDecoder).closed {
+		t.Fatalf("expected finish to close the decoder")
+	}
+}
+
+func TestOpusSessionIngestPropagatesDecodeErrors(t *testing.T) {
+	eng := engine.NewStubEngine(slog.New(slog.NewTextHandler(io.Discard, nil)), "tiny")
+	session := newTestOpusSession(eng)
+	session.decoder = failingOpusDecoder{}
+
+	if err := session.ingest(context.Background(), []byte{0x01, 0x02}); err == nil {
+		t.Fatalf("expected ingest to propagate a decode error")
+	}
+}
+
+type failingOpusDecoder struct{}
+
+func (failingOpusDecoder) Decode([]byte, []int16) (int, error) { return 0, errors.New("boom") }
+func (failingOpusDecoder) Close() error                        { return nil }
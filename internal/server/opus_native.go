@@ -0,0 +1,30 @@
+//go:build opus
+
+package server
+
+import "github.com/hraban/opus"
+
+// nativeOpusDecoder adapts github.com/hraban/opus's cgo binding of libopus
+// to the opusDecoder interface.
+type nativeOpusDecoder struct {
+	dec *opus.Decoder
+}
+
+// newOpusDecoder constructs a mono Opus decoder at sampleRate, binding
+// libopus through cgo. Build with -tags opus on hosts that have libopus
+// installed; see opus_stub.go for the default, dependency-free build.
+func newOpusDecoder(sampleRate int) (opusDecoder, error) {
+	dec, err := opus.NewDecoder(sampleRate, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &nativeOpusDecoder{dec: dec}, nil
+}
+
+func (d *nativeOpusDecoder) Decode(packet []byte, pcm []int16) (int, error) {
+	return d.dec.Decode(packet, pcm)
+}
+
+func (d *nativeOpusDecoder) Close() error {
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !opus
+
+package server
+
+// newOpusDecoder reports that opus decoding is not built in. The default
+// build excludes libopus so the adapter stays dependency-free; hosts that
+// want the WebRTC/WebSocket ingress must build with -tags opus (see
+// opus_native.go).
+func newOpusDecoder(sampleRate int) (opusDecoder, error) {
+	return nil, ErrOpusUnavailable
+}
@@ -0,0 +1,89 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	napv1 "github.com/nupi-ai/nupi/api/nap/v1"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/server"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// TestStreamTranscriptionHonoursOutputFormatMetadata sets the
+// nupi.engine.output_format stream metadata to "srt" and asserts the final
+// transcript's Text comes back rendered as an SRT cue instead of plain text.
+func TestStreamTranscriptionHonoursOutputFormatMetadata(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lis := bufconn.Listen(bufSize)
+	defer lis.Close()
+
+	grpcServer := grpc.NewServer()
+	t.Cleanup(grpcServer.Stop)
+
+	cfg := config.Config{ListenAddr: "bufconn", ModelVariant: "small", Language: "en", LogLevel: "debug"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	eng := wordTimingEngine{}
+	recorder := telemetry.NewRecorder(logger)
+	napv1.RegisterSpeechToTextServiceServer(grpcServer, server.New(cfg, logger, eng, recorder))
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil &&
+			!errors.Is(err, grpc.ErrServerStopped) &&
+			!errors.Is(err, net.ErrClosed) &&
+			err.Error() != "closed" {
+			t.Errorf("Serve() error: %v", err)
+		}
+	}()
+
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("DialContext error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client := napv1.NewSpeechToTextServiceClient(conn)
+	stream, err := client.StreamTranscription(ctx)
+	if err != nil {
+		t.Fatalf("StreamTranscription error: %v", err)
+	}
+
+	if err := stream.Send(&napv1.StreamTranscriptionRequest{
+		SessionId: "session-fmt",
+		StreamId:  "mic",
+		Metadata:  map[string]string{"nupi.engine.output_format": "srt"},
+		Flush:     true,
+	}); err != nil {
+		t.Fatalf("Send flush error: %v", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend error: %v", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv error: %v", err)
+	}
+	if !resp.GetFinal() {
+		t.Fatalf("expected final transcript")
+	}
+	if !strings.Contains(resp.GetText(), "-->") || !strings.Contains(resp.GetText(), "hello there") {
+		t.Fatalf("expected SRT-rendered text, got %q", resp.GetText())
+	}
+}
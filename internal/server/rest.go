@@ -0,0 +1,349 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// restMaxUploadBytes bounds how much of a multipart upload is buffered in
+// memory before net/http starts spilling the remainder to temp files.
+const restMaxUploadBytes = 32 << 20
+
+// restChunkMs is how much decoded audio the REST handler feeds the engine
+// per TranscribeSegment call. It plays the same role fixed-size streaming
+// chunks play for StreamTranscription, so VADModeEnergy engines still get a
+// chance to detect utterance boundaries inside a single upload instead of
+// decoding the whole file as one segment.
+const restChunkMs = 5000
+
+// restBytesPerMs is the byte rate of 16-bit mono PCM sampled at 16kHz (see
+// engine.pcmBytesPerMs), used to size chunks and derive segment timestamps
+// from the decoded upload.
+const restBytesPerMs = 2 * 16000 / 1000
+
+// TranscriptionHandler serves an OpenAI-compatible
+// POST /v1/audio/transcriptions endpoint on top of an engine.Engine, so any
+// existing Whisper REST client can point at the adapter unchanged.
+type TranscriptionHandler struct {
+	cfg     config.Config
+	log     *slog.Logger
+	engine  engine.Engine
+	metrics *telemetry.Recorder
+	// translate makes this handler serve POST /v1/audio/translations instead
+	// of /v1/audio/transcriptions: every segment is decoded with
+	// engine.Options.Translate set, so NativeEngine translates to English
+	// instead of transcribing in the source language. See
+	// NewTranslationHandler.
+	translate bool
+}
+
+// NewTranscriptionHandler returns an http.Handler serving
+// POST /v1/audio/transcriptions against eng.
+func NewTranscriptionHandler(cfg config.Config, logger *slog.Logger, eng engine.Engine, metrics *telemetry.Recorder) *TranscriptionHandler {
+	return newTranscriptionHandler(cfg, logger, eng, metrics, false)
+}
+
+// NewTranslationHandler returns an http.Handler serving
+// POST /v1/audio/translations against eng: identical to
+// NewTranscriptionHandler's upload handling, but every segment asks the
+// engine to translate its audio to English rather than transcribe it in the
+// source language.
+func NewTranslationHandler(cfg config.Config, logger *slog.Logger, eng engine.Engine, metrics *telemetry.Recorder) *TranscriptionHandler {
+	return newTranscriptionHandler(cfg, logger, eng, metrics, true)
+}
+
+func newTranscriptionHandler(cfg config.Config, logger *slog.Logger, eng engine.Engine, metrics *telemetry.Recorder, translate bool) *TranscriptionHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if eng == nil {
+		panic("server: engine must not be nil")
+	}
+	if metrics == nil {
+		metrics = telemetry.NewRecorder(logger)
+	}
+	log := logger.With("component", "server.rest")
+	if cfg.Alias != "" {
+		log = log.With("alias", cfg.Alias)
+	}
+	return &TranscriptionHandler{
+		cfg:       cfg,
+		log:       log,
+		engine:    eng,
+		metrics:   metrics,
+		translate: translate,
+	}
+}
+
+// resultSpan pairs an engine.Result with the stream-absolute span of audio
+// it was decoded from, so srt/vtt/verbose_json output can report timestamps.
+type resultSpan struct {
+	engine.Result
+	startMs uint64
+	endMs   uint64
+}
+
+func (h *TranscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseMultipartForm(restMaxUploadBytes); err != nil {
+		http.Error(w, fmt.Sprintf("invalid multipart upload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("missing %q field: %v", "file", err), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read upload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// model selects which loaded model to use in OpenAI's API; forwarded as
+	// engine.Options.Variant so a multi-model engine.EnginePool (see
+	// config.Config.Models) can route to it directly. Engines backed by a
+	// single model ignore the field.
+	variant := r.FormValue("model")
+	// temperature controls OpenAI's sampling; engine.Options has no
+	// sampling-temperature knob yet, so it is accepted but not forwarded.
+	_ = r.FormValue("temperature")
+	prompt := r.FormValue("prompt")
+
+	responseFormat := r.FormValue("response_format")
+	if responseFormat == "" {
+		responseFormat = "json"
+	}
+	switch responseFormat {
+	case "json", "text", "srt", "vtt", "verbose_json", "csv":
+	default:
+		http.Error(w, fmt.Sprintf("unsupported response_format %q", responseFormat), http.StatusBadRequest)
+		return
+	}
+
+	requestedLanguage := r.FormValue("language")
+	if requestedLanguage == "" {
+		requestedLanguage = h.cfg.Language
+	}
+	// X-Nupi-Lang-Iso1 lets a caller report its own client-side language
+	// detection, consulted when response_format's language field is
+	// "client" (see resolveLanguage).
+	meta := map[string]string{}
+	if iso1 := r.Header.Get("X-Nupi-Lang-Iso1"); iso1 != "" {
+		meta[isoLanguageMetadataKey] = iso1
+	}
+	language := resolveLanguage(requestedLanguage, meta)
+
+	pcm, sampleRate, err := decodeUpload(header.Filename, data)
+	if err != nil {
+		h.log.Error("failed to decode upload", "filename", header.Filename, "error", err)
+		http.Error(w, fmt.Sprintf("failed to decode audio: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	if sampleRate != 16000 {
+		http.Error(w, fmt.Sprintf("unsupported sample rate %d, expected 16000", sampleRate), http.StatusUnprocessableEntity)
+		return
+	}
+
+	sessionID := newUploadSessionID()
+	streamMetrics := h.metrics.StartStream(sessionID, "rest", nil,
+		telemetry.Attribute{Key: "model_variant", Value: h.cfg.ModelVariant},
+		telemetry.Attribute{Key: "language", Value: language},
+		telemetry.Attribute{Key: "accelerator", Value: h.cfg.Accelerator},
+		telemetry.Attribute{Key: "alias", Value: h.cfg.Alias},
+	)
+	var handlerErr error
+	defer func() { streamMetrics.Finish(handlerErr) }()
+
+	spans, err := h.transcribe(r.Context(), pcm, language, prompt, variant, streamMetrics)
+	if err != nil {
+		handlerErr = err
+		h.log.Error("engine failure", "session_id", sessionID, "error", err)
+		http.Error(w, fmt.Sprintf("transcription failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeTranscription(w, spans, language, responseFormat)
+}
+
+// transcribe feeds pcm to the engine restChunkMs at a time and collects the
+// results it emits, followed by a final Flush, mirroring the
+// segment-then-flush pattern StreamTranscription uses for gRPC callers.
+func (h *TranscriptionHandler) transcribe(ctx context.Context, pcm []byte, language, prompt, variant string, streamMetrics *telemetry.StreamMetrics) ([]resultSpan, error) {
+	chunkBytes := restChunkMs * restBytesPerMs
+
+	var (
+		elapsedMs uint64
+		sequence  uint64
+		spans     []resultSpan
+	)
+
+	for offset := 0; offset < len(pcm); offset += chunkBytes {
+		end := offset + chunkBytes
+		if end > len(pcm) {
+			end = len(pcm)
+		}
+		chunk := pcm[offset:end]
+		sequence++
+		durationMs := uint64(len(chunk)) / restBytesPerMs
+
+		streamMetrics.RecordSegment(sequence, len(chunk), false)
+		results, err := h.engine.TranscribeSegment(ctx, chunk, engine.Options{
+			Language:          language,
+			Sequence:          sequence,
+			Prompt:            prompt,
+			UtteranceOffsetMs: elapsedMs,
+			Variant:           variant,
+			Translate:         h.translate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("transcribe segment %d: %w", sequence, err)
+		}
+		for _, res := range results {
+			streamMetrics.RecordTranscript(sequence, res.Text, res.Final)
+			spans = append(spans, resultSpan{Result: res, startMs: elapsedMs, endMs: elapsedMs + durationMs})
+		}
+		elapsedMs += durationMs
+	}
+
+	streamMetrics.RecordFlush()
+	final, err := h.engine.Flush(ctx, engine.Options{Language: language, Final: true, Sequence: sequence, Variant: variant, Translate: h.translate})
+	if err != nil {
+		return nil, fmt.Errorf("flush: %w", err)
+	}
+	for _, res := range final {
+		streamMetrics.RecordTranscript(sequence, res.Text, res.Final)
+		spans = append(spans, resultSpan{Result: res, startMs: elapsedMs, endMs: elapsedMs})
+	}
+
+	return spans, nil
+}
+
+// decodeUpload returns 16-bit mono PCM and its sample rate for an uploaded
+// audio file. WAV uploads are parsed directly; any other container or codec
+// (MP3, FLAC, OGG, ...) is decoded by shelling out to ffmpeg, which is
+// expected to already be on PATH in deployments that enable this endpoint.
+func decodeUpload(filename string, data []byte) ([]byte, int, error) {
+	if len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WAVE" {
+		return decodeWAV(data)
+	}
+	return decodeWithFFmpeg(data, filepath.Ext(filename))
+}
+
+// decodeWAV parses a RIFF/WAVE container in memory, returning its raw PCM
+// data chunk and sample rate. It is the in-memory counterpart of the
+// loadPCM16LE fixture helper used by the native engine's tests.
+func decodeWAV(data []byte) ([]byte, int, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, fmt.Errorf("invalid wav header")
+	}
+
+	offset := 12
+	var (
+		sampleRate    int
+		audioFormat   uint16
+		channels      uint16
+		bitsPerSample uint16
+		audioData     []byte
+	)
+
+	for offset+8 <= len(data) {
+		chunkID := string(data[offset : offset+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		chunkStart := offset + 8
+		chunkEnd := chunkStart + chunkSize
+		if chunkEnd > len(data) {
+			return nil, 0, fmt.Errorf("chunk %s out of range", chunkID)
+		}
+		switch chunkID {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, 0, fmt.Errorf("fmt chunk too small")
+			}
+			audioFormat = binary.LittleEndian.Uint16(data[chunkStart : chunkStart+2])
+			channels = binary.LittleEndian.Uint16(data[chunkStart+2 : chunkStart+4])
+			sampleRate = int(binary.LittleEndian.Uint32(data[chunkStart+4 : chunkStart+8]))
+			bitsPerSample = binary.LittleEndian.Uint16(data[chunkStart+14 : chunkStart+16])
+		case "data":
+			audioData = data[chunkStart:chunkEnd]
+		}
+		// Chunks are word aligned.
+		offset = chunkEnd
+		if chunkSize%2 == 1 {
+			offset++
+		}
+	}
+
+	if audioFormat != 1 {
+		return nil, 0, fmt.Errorf("unsupported audio format %d", audioFormat)
+	}
+	if channels != 1 {
+		return nil, 0, fmt.Errorf("expected mono audio, got %d channels", channels)
+	}
+	if bitsPerSample != 16 {
+		return nil, 0, fmt.Errorf("expected 16-bit PCM, got %d", bitsPerSample)
+	}
+	if len(audioData) == 0 {
+		return nil, 0, fmt.Errorf("no data chunk found")
+	}
+	return audioData, sampleRate, nil
+}
+
+// decodeWithFFmpeg shells out to ffmpeg to transcode an arbitrary container
+// (MP3, FLAC, OGG, ...) into raw mono 16kHz 16-bit PCM on stdout.
+func decodeWithFFmpeg(data []byte, ext string) ([]byte, int, error) {
+	cmd := exec.Command("ffmpeg",
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-f", "s16le", "-ar", "16000", "-ac", "1",
+		"pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(data)
+	out, err := cmd.Output()
+	if err != nil {
+		if ext == "" {
+			ext = "(unknown)"
+		}
+		return nil, 0, fmt.Errorf("ffmpeg decode of %s upload: %w", ext, err)
+	}
+	return out, 16000, nil
+}
+
+// newUploadSessionID returns a random hex identifier used to correlate one
+// REST upload's telemetry and logs, mirroring telemetry.Tracer's span IDs.
+func newUploadSessionID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "rest-" + hex.EncodeToString(buf)
+}
+
+func joinFinalText(spans []resultSpan) string {
+	var texts []string
+	for _, s := range spans {
+		if s.Final && strings.TrimSpace(s.Text) != "" {
+			texts = append(texts, strings.TrimSpace(s.Text))
+		}
+	}
+	return strings.Join(texts, " ")
+}
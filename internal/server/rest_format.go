@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/format"
+)
+
+// verboseTranscriptionWord mirrors OpenAI's verbose_json per-word timing
+// shape, derived from engine.Word.
+type verboseTranscriptionWord struct {
+	Word        string  `json:"word"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	SpeakerTurn bool    `json:"speaker_turn,omitempty"`
+}
+
+// verboseTranscriptionSegment mirrors OpenAI's verbose_json segment shape.
+type verboseTranscriptionSegment struct {
+	ID               int                        `json:"id"`
+	Start            float64                    `json:"start"`
+	End              float64                    `json:"end"`
+	Text             string                     `json:"text"`
+	AvgLogprob       float32                    `json:"avg_logprob"`
+	NoSpeechProb     float32                    `json:"no_speech_prob"`
+	CompressionRatio float32                    `json:"compression_ratio"`
+	Words            []verboseTranscriptionWord `json:"words,omitempty"`
+}
+
+// verboseTranscription mirrors OpenAI's verbose_json response shape.
+type verboseTranscription struct {
+	Task     string                        `json:"task"`
+	Language string                        `json:"language"`
+	Duration float64                       `json:"duration"`
+	Text     string                        `json:"text"`
+	Segments []verboseTranscriptionSegment `json:"segments"`
+}
+
+// writeTranscription renders spans in the requested OpenAI response_format.
+func writeTranscription(w http.ResponseWriter, spans []resultSpan, language, responseFormat string) {
+	finals := finalSpans(spans)
+	text := joinFinalText(spans)
+
+	switch responseFormat {
+	case "verbose_json":
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(buildVerboseTranscription(finals, language, text))
+	default:
+		f := format.Format(responseFormat)
+		if !format.Valid(f) {
+			f = format.Text
+		}
+		w.Header().Set("Content-Type", format.ContentType(f))
+		_ = format.Render(w, f, spansToSegments(finals))
+	}
+}
+
+// spansToSegments adapts resultSpan (this package's REST-specific pairing of
+// an engine.Result with its decoded offset) into the ingress-agnostic
+// shape internal/format renders.
+func spansToSegments(spans []resultSpan) []format.Segment {
+	segments := make([]format.Segment, 0, len(spans))
+	for _, s := range spans {
+		segments = append(segments, format.Segment{Text: s.Text, StartMs: s.startMs, EndMs: s.endMs, Words: s.Words})
+	}
+	return segments
+}
+
+func finalSpans(spans []resultSpan) []resultSpan {
+	var out []resultSpan
+	for _, s := range spans {
+		if s.Final && strings.TrimSpace(s.Text) != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func buildVerboseTranscription(finals []resultSpan, language, text string) verboseTranscription {
+	segments := make([]verboseTranscriptionSegment, 0, len(finals))
+	var durationMs uint64
+	for i, s := range finals {
+		segments = append(segments, verboseTranscriptionSegment{
+			ID:               i,
+			Start:            msToSeconds(s.startMs),
+			End:              msToSeconds(s.endMs),
+			Text:             strings.TrimSpace(s.Text),
+			AvgLogprob:       s.AvgLogprob,
+			NoSpeechProb:     s.NoSpeechProb,
+			CompressionRatio: s.CompressionRatio,
+			Words:            buildVerboseWords(s.Words),
+		})
+		if s.endMs > durationMs {
+			durationMs = s.endMs
+		}
+	}
+	return verboseTranscription{
+		Task:     "transcribe",
+		Language: language,
+		Duration: msToSeconds(durationMs),
+		Text:     text,
+		Segments: segments,
+	}
+}
+
+func buildVerboseWords(words []engine.Word) []verboseTranscriptionWord {
+	if len(words) == 0 {
+		return nil
+	}
+	out := make([]verboseTranscriptionWord, len(words))
+	for i, word := range words {
+		out[i] = verboseTranscriptionWord{
+			Word:        word.Text,
+			Start:       msToSeconds(word.StartMs),
+			End:         msToSeconds(word.EndMs),
+			SpeakerTurn: word.SpeakerTurn,
+		}
+	}
+	return out
+}
+
+func msToSeconds(ms uint64) float64 {
+	return float64(ms) / 1000
+}
+
+// formatSRTTimestamp renders ms as SRT's "HH:MM:SS,mmm" timestamp.
+func formatSRTTimestamp(ms uint64) string {
+	return format.SRTTimestamp(ms)
+}
+
+// formatVTTTimestamp renders ms as WebVTT's "HH:MM:SS.mmm" timestamp.
+func formatVTTTimestamp(ms uint64) string {
+	return format.VTTTimestamp(ms)
+}
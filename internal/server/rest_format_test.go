@@ -0,0 +1,114 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/server"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// wordTimingEngine is a stub-like Engine that synthesises fake word timings
+// on its final transcript, so tests can exercise verbose_json/srt/vtt/csv
+// rendering without a real whisper.cpp backend.
+type wordTimingEngine struct{}
+
+func (wordTimingEngine) TranscribeSegment(ctx context.Context, audio []byte, opts engine.Options) ([]engine.Result, error) {
+	return nil, nil
+}
+
+func (wordTimingEngine) Flush(ctx context.Context, opts engine.Options) ([]engine.Result, error) {
+	return []engine.Result{{
+		Text:  "hello there",
+		Final: true,
+		Words: []engine.Word{
+			{Text: "hello", StartMs: 0, EndMs: 400, Probability: 0.9},
+			{Text: "there", StartMs: 400, EndMs: 900, Probability: 0.8},
+		},
+		StartMs: 0,
+		EndMs:   900,
+	}}, nil
+}
+
+func (wordTimingEngine) Close() error { return nil }
+
+func newWordTimingUploadRequest(t *testing.T, responseFormat string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "sample.wav")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	if _, err := part.Write(buildWAV(t, make([]int16, 1600))); err != nil {
+		t.Fatalf("write wav part: %v", err)
+	}
+	if responseFormat != "" {
+		if err := w.WriteField("response_format", responseFormat); err != nil {
+			t.Fatalf("WriteField: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/v1/audio/transcriptions", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	cfg := config.Config{ModelVariant: "base", Language: "en", Accelerator: "cpu"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	recorder := telemetry.NewRecorder(logger)
+	handler := server.NewTranscriptionHandler(cfg, logger, wordTimingEngine{}, recorder)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTranscriptionHandlerVerboseJSONIncludesWordTimings(t *testing.T) {
+	rec := newWordTimingUploadRequest(t, "verbose_json")
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var payload struct {
+		Segments []struct {
+			Words []struct {
+				Word  string  `json:"word"`
+				Start float64 `json:"start"`
+				End   float64 `json:"end"`
+			} `json:"words"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Segments) != 1 || len(payload.Segments[0].Words) != 2 {
+		t.Fatalf("expected one segment with two word timings, got %+v", payload)
+	}
+	if payload.Segments[0].Words[0].Word != "hello" || payload.Segments[0].Words[0].End != 0.4 {
+		t.Fatalf("unexpected first word timing: %+v", payload.Segments[0].Words[0])
+	}
+}
+
+func TestTranscriptionHandlerCSVRendersOneRowPerSegment(t *testing.T) {
+	rec := newWordTimingUploadRequest(t, "csv")
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Fatalf("content-type = %q", ct)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("hello there")) {
+		t.Fatalf("expected csv body to contain transcript text: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func wavFixture(t *testing.T, samples []int16) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	for _, s := range samples {
+		if err := binary.Write(&data, binary.LittleEndian, s); err != nil {
+			t.Fatalf("write sample: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+data.Len()))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))     // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))     // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(16000)) // sample rate
+	binary.Write(&buf, binary.LittleEndian, uint32(16000*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(data.Len()))
+	buf.Write(data.Bytes())
+
+	return buf.Bytes()
+}
+
+func TestDecodeWAVRoundTrip(t *testing.T) {
+	samples := []int16{1, 2, 3, 4}
+	pcm, sampleRate, err := decodeWAV(wavFixture(t, samples))
+	if err != nil {
+		t.Fatalf("decodeWAV: %v", err)
+	}
+	if sampleRate != 16000 {
+		t.Fatalf("sampleRate = %d, want 16000", sampleRate)
+	}
+	if len(pcm) != len(samples)*2 {
+		t.Fatalf("pcm length = %d, want %d", len(pcm), len(samples)*2)
+	}
+}
+
+func TestDecodeWAVRejectsNonPCM(t *testing.T) {
+	if _, _, err := decodeWAV([]byte("not a wav file")); err == nil {
+		t.Fatalf("expected error for invalid wav header")
+	}
+}
+
+func TestFormatSRTTimestamp(t *testing.T) {
+	got := formatSRTTimestamp(3_723_045)
+	if want := "01:02:03,045"; got != want {
+		t.Fatalf("formatSRTTimestamp = %q, want %q", got, want)
+	}
+}
+
+func TestFormatVTTTimestamp(t *testing.T) {
+	got := formatVTTTimestamp(65_500)
+	if want := "00:01:05.500"; got != want {
+		t.Fatalf("formatVTTTimestamp = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/server"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// buildWAV encodes samples as a minimal mono 16-bit 16kHz RIFF/WAVE file.
+func buildWAV(t *testing.T, samples []int16) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	for _, s := range samples {
+		binary.Write(&data, binary.LittleEndian, s)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+data.Len()))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint32(16000))
+	binary.Write(&buf, binary.LittleEndian, uint32(16000*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(data.Len()))
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
+
+func newUploadRequest(t *testing.T, wav []byte, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "sample.wav")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	if _, err := part.Write(wav); err != nil {
+		t.Fatalf("write wav part: %v", err)
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatalf("WriteField(%s): %v", k, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/v1/audio/transcriptions", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	cfg := config.Config{ModelVariant: "base", Language: "auto", Accelerator: "cpu"}
+	eng := engine.NewStubEngine(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg.ModelVariant)
+	recorder := telemetry.NewRecorder(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := server.NewTranscriptionHandler(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), eng, recorder)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTranscriptionHandlerJSON(t *testing.T) {
+	samples := make([]int16, 1600) // 100ms of silence
+	rec := newUploadRequest(t, buildWAV(t, samples), nil)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Text == "" {
+		t.Fatalf("expected non-empty transcript text")
+	}
+}
+
+func TestTranscriptionHandlerSRT(t *testing.T) {
+	samples := make([]int16, 1600)
+	rec := newUploadRequest(t, buildWAV(t, samples), map[string]string{"response_format": "srt"})
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-subrip; charset=utf-8" {
+		t.Fatalf("content-type = %q", ct)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("-->")) {
+		t.Fatalf("expected SRT timing arrow in body: %s", rec.Body.String())
+	}
+}
+
+func TestTranscriptionHandlerRejectsUnsupportedFormat(t *testing.T) {
+	samples := make([]int16, 1600)
+	rec := newUploadRequest(t, buildWAV(t, samples), map[string]string{"response_format": "xml"})
+
+	if rec.Code != 400 {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestTranslationHandlerJSON(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", "sample.wav")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	samples := make([]int16, 1600) // 100ms of silence
+	if _, err := part.Write(buildWAV(t, samples)); err != nil {
+		t.Fatalf("write wav part: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/v1/audio/translations", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	cfg := config.Config{ModelVariant: "base", Language: "auto", Accelerator: "cpu"}
+	eng := engine.NewStubEngine(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg.ModelVariant)
+	recorder := telemetry.NewRecorder(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := server.NewTranslationHandler(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), eng, recorder)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Text == "" {
+		t.Fatalf("expected non-empty transcript text")
+	}
+}
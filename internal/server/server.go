@@ -1,8 +1,11 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"io"
 	"log/slog"
+	"strconv"
 	"time"
 
 	napv1 "github.com/nupi-ai/nupi/api/nap/v1"
@@ -10,9 +13,36 @@ import (
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/adapterinfo"
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/format"
 	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/vad"
 )
 
+// variantMetadataKey is the stream metadata key a caller can set to pin a
+// stream to one entry of a multi-model engine.EnginePool (see
+// config.Config.Models), overriding language-based routing for every
+// TranscribeSegment/Flush call on that stream.
+const variantMetadataKey = "nupi.engine.variant"
+
+// outputFormatMetadataKey is the stream metadata key a caller can set to
+// render final transcripts as "json", "srt", "vtt", or "csv" instead of
+// plain text, mirroring REST's response_format. See internal/format.
+const outputFormatMetadataKey = "nupi.engine.output_format"
+
+// wordsMetadataKey is the response metadata key sendResults attaches a
+// transcript's word-aligned timings and TinyDiarize speaker-turn markers
+// under, JSON-encoded as []engine.Word. napv1.Transcript has no dedicated
+// field for either, so this follows the same metadata-smuggling precedent
+// as adapterinfo.TranscriptMetadata until the shared nupi proto grows one.
+const wordsMetadataKey = "nupi.engine.words"
+
+// stableOffsetMetadataKey is the response metadata key sendResults attaches
+// a transcript's engine.Result.StableOffset under, strconv-encoded, when
+// config.Config.StreamStabilize is enabled. napv1.Transcript has no
+// dedicated field for it either, so this follows the same
+// metadata-smuggling precedent as wordsMetadataKey.
+const stableOffsetMetadataKey = "nupi.engine.stable_offset"
+
 // Server implements the SpeechToTextService and provides stubbed transcripts.
 type Server struct {
 	napv1.UnimplementedSpeechToTextServiceServer
@@ -21,6 +51,7 @@ type Server struct {
 	log     *slog.Logger
 	engine  engine.Engine
 	metrics *telemetry.Recorder
+	vadGate *vadGate
 }
 
 // New returns a new Server instance.
@@ -34,15 +65,31 @@ func New(cfg config.Config, logger *slog.Logger, engine engine.Engine, metrics *
 	if metrics == nil {
 		metrics = telemetry.NewRecorder(logger)
 	}
+	log := logger.With(
+		"component", "server",
+		"model_variant", cfg.ModelVariant,
+		"language", cfg.Language,
+	)
+	if cfg.Alias != "" {
+		log = log.With("alias", cfg.Alias)
+	}
+
+	detector, err := vad.New(cfg.VADGateBackend, vad.Config{
+		MinSpeechMs:  cfg.VADGateMinSpeechMs,
+		MinSilenceMs: cfg.VADGateMinSilenceMs,
+		PreRollMs:    cfg.VADGatePreRollMs,
+	})
+	if err != nil {
+		log.Warn("failed to construct VAD gate, falling back to disabled", "backend", cfg.VADGateBackend, "error", err)
+		detector, _ = vad.New(vad.BackendNone, vad.Config{})
+	}
+
 	return &Server{
-		cfg: cfg,
-		log: logger.With(
-			"component", "server",
-			"model_variant", cfg.ModelVariant,
-			"language", cfg.Language,
-		),
+		cfg:     cfg,
+		log:     log,
 		engine:  engine,
 		metrics: metrics,
+		vadGate: newVADGate(detector),
 	}
 }
 
@@ -53,6 +100,10 @@ func (s *Server) StreamTranscription(stream napv1.SpeechToTextService_StreamTran
 	var (
 		initLogged    bool
 		streamMetrics *telemetry.StreamMetrics
+		variant       string
+		sessionKey    string
+		outputFormat  format.Format
+		lastSequence  uint64
 	)
 	ctx := stream.Context()
 	defer func() {
@@ -65,7 +116,13 @@ func (s *Server) StreamTranscription(stream napv1.SpeechToTextService_StreamTran
 		req, err := stream.Recv()
 		if err != nil {
 			if err == io.EOF {
-				return nil
+				if !initLogged {
+					return nil
+				}
+				// The client closed its send side without an explicit Flush
+				// or a Last segment, so flush here or the stabilized/
+				// segmented sliding window never emits its final suffix.
+				return s.flushStream(ctx, stream, lastSequence, variant, sessionKey, outputFormat, streamMetrics)
 			}
 			s.log.Error("failed to receive request", "error", err)
 			return err
@@ -75,7 +132,21 @@ func (s *Server) StreamTranscription(stream napv1.SpeechToTextService_StreamTran
 		}
 
 		if !initLogged {
-			streamMetrics = s.metrics.StartStream(req.GetSessionId(), req.GetStreamId(), req.GetMetadata())
+			variant = req.GetMetadata()[variantMetadataKey]
+			sessionKey = req.GetSessionId()
+			if sessionKey == "" {
+				sessionKey = req.GetStreamId()
+			}
+			outputFormat = format.Format(req.GetMetadata()[outputFormatMetadataKey])
+			if !format.Valid(outputFormat) {
+				outputFormat = format.Text
+			}
+			streamMetrics = s.metrics.StartStream(req.GetSessionId(), req.GetStreamId(), req.GetMetadata(),
+				telemetry.Attribute{Key: "model_variant", Value: s.cfg.ModelVariant},
+				telemetry.Attribute{Key: "language", Value: s.cfg.Language},
+				telemetry.Attribute{Key: "accelerator", Value: s.cfg.Accelerator},
+				telemetry.Attribute{Key: "alias", Value: s.cfg.Alias},
+			)
 			s.log.Info("stream opened",
 				"session_id", req.GetSessionId(),
 				"stream_id", req.GetStreamId(),
@@ -88,44 +159,54 @@ func (s *Server) StreamTranscription(stream napv1.SpeechToTextService_StreamTran
 		var sequence uint64
 		if segment != nil {
 			sequence = segment.GetSequence()
+			lastSequence = sequence
 		}
 
 		if segment != nil && len(segment.GetAudio()) > 0 {
+			audio := segment.GetAudio()
+			final := req.GetFlush() || segment.GetLast()
 			if streamMetrics != nil {
-				streamMetrics.RecordSegment(sequence, len(segment.GetAudio()), req.GetFlush() || segment.GetLast())
-			}
-			start := time.Now()
-			results, err := s.engine.TranscribeSegment(ctx, segment.GetAudio(), engine.Options{
-				Language: s.cfg.Language,
-				Final:    req.GetFlush() || segment.GetLast(),
-				Sequence: sequence,
-			})
-			if err != nil {
-				s.log.Error("engine segment failure", "error", err)
-				return err
+				streamMetrics.RecordSegment(sequence, len(audio), final)
 			}
+
+			speech, isSpeech := s.vadGate.filter(audio)
 			if streamMetrics != nil {
-				streamMetrics.RecordInferenceDuration(time.Since(start))
+				streamMetrics.RecordVoiceActivity(isSpeech, pcmDurationMs(len(audio)))
 			}
-			if err := s.sendResults(stream, sequence, results, streamMetrics); err != nil {
-				return err
+
+			if isSpeech {
+				spanCtx, span := s.metrics.Tracer().Start(ctx, "TranscribeSegment",
+					telemetry.Attribute{Key: "session_id", Value: req.GetSessionId()},
+					telemetry.Attribute{Key: "model_variant", Value: s.cfg.ModelVariant},
+					telemetry.Attribute{Key: "language", Value: s.cfg.Language},
+					telemetry.Attribute{Key: "accelerator", Value: s.cfg.Accelerator},
+					telemetry.Attribute{Key: "alias", Value: s.cfg.Alias},
+				)
+				start := time.Now()
+				results, err := s.engine.TranscribeSegment(spanCtx, speech, engine.Options{
+					Language:     s.cfg.Language,
+					Final:        final,
+					Sequence:     sequence,
+					Variant:      variant,
+					SessionID:    sessionKey,
+					OutputFormat: string(outputFormat),
+				})
+				span.End(err)
+				if err != nil {
+					s.log.Error("engine segment failure", "error", err)
+					return err
+				}
+				if streamMetrics != nil {
+					streamMetrics.RecordInferenceDuration(time.Since(start))
+				}
+				if err := s.sendResults(stream, sequence, results, streamMetrics, outputFormat); err != nil {
+					return err
+				}
 			}
 		}
 
 		if req.GetFlush() {
-			if streamMetrics != nil {
-				streamMetrics.RecordFlush()
-			}
-			start := time.Now()
-			results, err := s.engine.Flush(ctx, engine.Options{Language: s.cfg.Language, Final: true})
-			if err != nil {
-				s.log.Error("engine flush failure", "error", err)
-				return err
-			}
-			if streamMetrics != nil {
-				streamMetrics.RecordInferenceDuration(time.Since(start))
-			}
-			if err := s.sendResults(stream, sequence, results, streamMetrics); err != nil {
+			if err := s.flushStream(ctx, stream, sequence, variant, sessionKey, outputFormat, streamMetrics); err != nil {
 				return err
 			}
 			s.log.Info("stream flushed",
@@ -137,17 +218,66 @@ func (s *Server) StreamTranscription(stream napv1.SpeechToTextService_StreamTran
 	}
 }
 
-func (s *Server) sendResults(stream napv1.SpeechToTextService_StreamTranscriptionServer, sequence uint64, results []engine.Result, metrics *telemetry.StreamMetrics) error {
+// flushStream asks s.engine to emit whatever stable output it was holding
+// back (the final suffix of a sliding-window stabilizer, a segmenter's
+// buffered tail, ...) for sessionKey and sends the results on stream.
+// Called both for an explicit client Flush and when the client closes its
+// send side without one, since either way no more audio is coming.
+func (s *Server) flushStream(ctx context.Context, stream napv1.SpeechToTextService_StreamTranscriptionServer, sequence uint64, variant, sessionKey string, outputFormat format.Format, streamMetrics *telemetry.StreamMetrics) error {
+	if streamMetrics != nil {
+		streamMetrics.RecordFlush()
+	}
+	spanCtx, span := s.metrics.Tracer().Start(ctx, "Flush",
+		telemetry.Attribute{Key: "session_id", Value: sessionKey},
+		telemetry.Attribute{Key: "model_variant", Value: s.cfg.ModelVariant},
+		telemetry.Attribute{Key: "language", Value: s.cfg.Language},
+		telemetry.Attribute{Key: "accelerator", Value: s.cfg.Accelerator},
+		telemetry.Attribute{Key: "alias", Value: s.cfg.Alias},
+	)
+	start := time.Now()
+	results, err := s.engine.Flush(spanCtx, engine.Options{Language: s.cfg.Language, Final: true, Variant: variant, SessionID: sessionKey, OutputFormat: string(outputFormat)})
+	span.End(err)
+	if err != nil {
+		s.log.Error("engine flush failure", "error", err)
+		return err
+	}
+	if streamMetrics != nil {
+		streamMetrics.RecordInferenceDuration(time.Since(start))
+	}
+	return s.sendResults(stream, sequence, results, streamMetrics, outputFormat)
+}
+
+func (s *Server) sendResults(stream napv1.SpeechToTextService_StreamTranscriptionServer, sequence uint64, results []engine.Result, metrics *telemetry.StreamMetrics, outputFormat format.Format) error {
 	for _, res := range results {
 		if metrics != nil {
 			metrics.RecordTranscript(sequence, res.Text, res.Final)
 		}
+		text := res.Text
+		if res.Final && outputFormat != "" && outputFormat != format.Text {
+			rendered, err := format.RenderString(outputFormat, format.FromResults([]engine.Result{res}))
+			if err != nil {
+				s.log.Error("failed to render transcript", "format", outputFormat, "error", err)
+				return err
+			}
+			text = rendered
+		}
+		metadata := adapterinfo.TranscriptMetadata(s.cfg.ModelVariant, s.cfg.Language)
+		if len(res.Words) > 0 {
+			if encoded, err := json.Marshal(res.Words); err != nil {
+				s.log.Error("failed to encode word timings", "error", err)
+			} else {
+				metadata[wordsMetadataKey] = string(encoded)
+			}
+		}
+		if s.cfg.StreamStabilize {
+			metadata[stableOffsetMetadataKey] = strconv.Itoa(res.StableOffset)
+		}
 		transcript := &napv1.Transcript{
 			Sequence:   sequence,
-			Text:       res.Text,
+			Text:       text,
 			Confidence: res.Confidence,
 			Final:      res.Final,
-			Metadata:   adapterinfo.TranscriptMetadata(s.cfg.ModelVariant, s.cfg.Language),
+			Metadata:   metadata,
 		}
 		if err := stream.Send(transcript); err != nil {
 			s.log.Error("failed to send transcript", "error", err)
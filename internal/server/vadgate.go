@@ -0,0 +1,46 @@
+package server
+
+import "github.com/nupi-ai/plugin-stt-local-whisper/internal/vad"
+
+// pcmBytesPerMs is the byte rate of 16-bit mono PCM sampled at 16kHz, the
+// adapter's fixed ingress format, used to translate a gated chunk's byte
+// count into milliseconds for the stt.voice_activity.ms heartbeat.
+const pcmBytesPerMs = 2 * 16000 / 1000
+
+// vadGate runs a vad.Detector over each incoming audio chunk independently
+// to decide whether any of it is worth decoding. It does not buffer audio
+// across chunks: a segment onset or pre-roll that straddles a chunk
+// boundary may be clipped, but that's an acceptable trade against buffering
+// a whole stream's audio in a server that already lets engine.SegmentedEngine
+// (config.VADModeEnergy) buffer at the utterance level when finer-grained
+// boundaries matter.
+type vadGate struct {
+	detector vad.Detector
+}
+
+func newVADGate(detector vad.Detector) *vadGate {
+	return &vadGate{detector: detector}
+}
+
+// filter returns the PCM16LE bytes of pcm's detected speech segments,
+// concatenated in order, and whether any speech was found at all.
+func (g *vadGate) filter(pcm []byte) (speech []byte, found bool) {
+	samples := vad.SamplesFromPCM16(pcm)
+	segments := g.detector.Detect(samples)
+	if len(segments) == 0 {
+		return nil, false
+	}
+	for _, seg := range segments {
+		speech = append(speech, vad.PCM16FromSamples(samples[seg.StartSample:seg.EndSample])...)
+	}
+	return speech, len(speech) > 0
+}
+
+// Close releases the underlying vad.Detector.
+func (g *vadGate) Close() error {
+	return g.detector.Close()
+}
+
+func pcmDurationMs(n int) int {
+	return n / pcmBytesPerMs
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/vad"
+)
+
+func TestVADGateNoneBackendPassesEverythingThrough(t *testing.T) {
+	detector, err := vad.New(vad.BackendNone, vad.Config{})
+	if err != nil {
+		t.Fatalf("vad.New() error: %v", err)
+	}
+	gate := newVADGate(detector)
+
+	pcm := make([]byte, 640) // 20ms at 16kHz/mono/16-bit
+	speech, found := gate.filter(pcm)
+	if !found {
+		t.Fatalf("expected BackendNone to always report speech found")
+	}
+	if len(speech) != len(pcm) {
+		t.Fatalf("expected the whole chunk back, got %d of %d bytes", len(speech), len(pcm))
+	}
+}
+
+func TestVADGateEnergyBackendDropsSilence(t *testing.T) {
+	detector, err := vad.New(vad.BackendEnergy, vad.Config{})
+	if err != nil {
+		t.Fatalf("vad.New() error: %v", err)
+	}
+	gate := newVADGate(detector)
+
+	pcm := make([]byte, 640)
+	speech, found := gate.filter(pcm)
+	if found {
+		t.Fatalf("expected silence to be dropped, got %d bytes", len(speech))
+	}
+}
+
+func TestPCMDurationMs(t *testing.T) {
+	if got := pcmDurationMs(640); got != 20 {
+		t.Fatalf("expected 20ms for 640 bytes at 16kHz/mono/16-bit, got %d", got)
+	}
+}
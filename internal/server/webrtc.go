@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/pion/webrtc/v3"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// WebRTCHandler serves POST /v1/webrtc/offer: it accepts a browser's SDP
+// offer, answers with a PeerConnection that decodes the inbound Opus audio
+// track and feeds it through the same engine.Engine pipeline
+// StreamTranscription and TranscriptionHandler use. Transcripts are sent
+// back over a "transcripts" DataChannel the browser opens alongside its
+// audio track, one JSON-encoded engine.Result per message.
+type WebRTCHandler struct {
+	cfg     config.Config
+	log     *slog.Logger
+	engine  engine.Engine
+	metrics *telemetry.Recorder
+}
+
+// NewWebRTCHandler returns an http.Handler serving POST /v1/webrtc/offer
+// against eng.
+func NewWebRTCHandler(cfg config.Config, logger *slog.Logger, eng engine.Engine, metrics *telemetry.Recorder) *WebRTCHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if eng == nil {
+		panic("server: engine must not be nil")
+	}
+	if metrics == nil {
+		metrics = telemetry.NewRecorder(logger)
+	}
+	log := logger.With("component", "server.webrtc")
+	if cfg.Alias != "" {
+		log = log.With("alias", cfg.Alias)
+	}
+	return &WebRTCHandler{cfg: cfg, log: log, engine: eng, metrics: metrics}
+}
+
+// offerRequest is the body POST /v1/webrtc/offer expects: a browser's SDP
+// offer plus the same optional "language" override REST's response_format
+// path accepts.
+type offerRequest struct {
+	SDP      string `json:"sdp"`
+	Type     string `json:"type"`
+	Language string `json:"language"`
+}
+
+func (h *WebRTCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req offerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid offer: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	language := req.Language
+	if language == "" {
+		language = h.cfg.Language
+	}
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		h.log.Error("failed to create peer connection", "error", err)
+		http.Error(w, fmt.Sprintf("failed to create peer connection: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	sessionID := newUploadSessionID()
+	streamMetrics := h.metrics.StartStream(sessionID, "webrtc", nil,
+		telemetry.Attribute{Key: "model_variant", Value: h.cfg.ModelVariant},
+		telemetry.Attribute{Key: "language", Value: language},
+		telemetry.Attribute{Key: "accelerator", Value: h.cfg.Accelerator},
+		telemetry.Attribute{Key: "alias", Value: h.cfg.Alias},
+	)
+
+	h.attachTrackHandler(pc, streamMetrics, language, sessionID)
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{
+		Type: webrtc.NewSDPType(req.Type),
+		SDP:  req.SDP,
+	}); err != nil {
+		streamMetrics.Finish(err)
+		_ = pc.Close()
+		h.log.Error("failed to set remote description", "session_id", sessionID, "error", err)
+		http.Error(w, fmt.Sprintf("invalid offer: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		streamMetrics.Finish(err)
+		_ = pc.Close()
+		h.log.Error("failed to create answer", "session_id", sessionID, "error", err)
+		http.Error(w, fmt.Sprintf("failed to create answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	gatherComplete := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		streamMetrics.Finish(err)
+		_ = pc.Close()
+		h.log.Error("failed to set local description", "session_id", sessionID, "error", err)
+		http.Error(w, fmt.Sprintf("failed to set local description: %v", err), http.StatusInternalServerError)
+		return
+	}
+	<-gatherComplete
+
+	h.log.Info("webrtc offer negotiated", "session_id", sessionID, "language", language)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(offerRequest{
+		SDP:  pc.LocalDescription().SDP,
+		Type: pc.LocalDescription().Type.String(),
+	})
+}
+
+// attachTrackHandler wires pc's inbound audio track to an opusSession, and
+// any DataChannel the browser opens to receive its transcripts. The
+// PeerConnection closing (browser navigates away, network drop, explicit
+// hangup) flushes the session and finishes streamMetrics.
+func (h *WebRTCHandler) attachTrackHandler(pc *webrtc.PeerConnection, streamMetrics *telemetry.StreamMetrics, language, sessionID string) {
+	var transcripts *webrtc.DataChannel
+
+	pc.OnDataChannel(func(dc *webrtc.DataChannel) {
+		if dc.Label() == "transcripts" {
+			transcripts = dc
+		}
+	})
+
+	pc.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if track.Kind() != webrtc.RTPCodecTypeAudio {
+			return
+		}
+
+		session, err := newOpusSession(h.log, h.engine, streamMetrics, language)
+		if err != nil {
+			h.log.Error("failed to start opus session", "session_id", sessionID, "error", err)
+			return
+		}
+
+		ctx := context.Background()
+		for {
+			packet, _, err := track.ReadRTP()
+			if err != nil {
+				results, finishErr := session.finish(ctx)
+				streamMetrics.Finish(finishErr)
+				if finishErr == nil {
+					h.sendTranscripts(transcripts, results)
+				}
+				return
+			}
+			if err := session.ingest(ctx, packet.Payload); err != nil {
+				h.log.Error("failed to decode opus packet", "session_id", sessionID, "error", err)
+				continue
+			}
+		}
+	})
+}
+
+// sendTranscripts relays final results over the browser's "transcripts"
+// DataChannel, when it opened one; REST and gRPC callers get results
+// synchronously, but the WebRTC audio track carries no return channel of
+// its own.
+func (h *WebRTCHandler) sendTranscripts(dc *webrtc.DataChannel, results []engine.Result) {
+	if dc == nil {
+		return
+	}
+	for _, res := range results {
+		payload, err := json.Marshal(res)
+		if err != nil {
+			continue
+		}
+		_ = dc.SendText(string(payload))
+	}
+}
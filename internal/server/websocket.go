@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/config"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/engine"
+	"github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+)
+
+// maxOpusWSFrameBytes bounds a single binary websocket message: Opus's
+// largest practical frame (120ms at the highest bitrates) is well under 4KB,
+// so this only guards against a misbehaving client.
+const maxOpusWSFrameBytes = 4096
+
+// WebSocketHandler serves the Opus-in-WebSocket counterpart to
+// WebRTCHandler's SDP-negotiated ingress, for callers that want to stream
+// Opus frames without standing up a full PeerConnection. Each binary
+// websocket message is one Opus packet; decoded, resampled audio is fed
+// through the same opusSession pipeline.
+type WebSocketHandler struct {
+	cfg     config.Config
+	log     *slog.Logger
+	engine  engine.Engine
+	metrics *telemetry.Recorder
+}
+
+// NewWebSocketHandler returns an http.Handler serving the Opus-in-WebSocket
+// ingress against eng.
+func NewWebSocketHandler(cfg config.Config, logger *slog.Logger, eng engine.Engine, metrics *telemetry.Recorder) *WebSocketHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if eng == nil {
+		panic("server: engine must not be nil")
+	}
+	if metrics == nil {
+		metrics = telemetry.NewRecorder(logger)
+	}
+	log := logger.With("component", "server.websocket")
+	if cfg.Alias != "" {
+		log = log.With("alias", cfg.Alias)
+	}
+	return &WebSocketHandler{cfg: cfg, log: log, engine: eng, metrics: metrics}
+}
+
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Handler(h.handle).ServeHTTP(w, r)
+}
+
+func (h *WebSocketHandler) handle(ws *websocket.Conn) {
+	defer ws.Close()
+
+	language := queryValueOrDefault(ws.Request(), "language", h.cfg.Language)
+	sessionID := newUploadSessionID()
+	streamMetrics := h.metrics.StartStream(sessionID, "websocket", nil,
+		telemetry.Attribute{Key: "model_variant", Value: h.cfg.ModelVariant},
+		telemetry.Attribute{Key: "language", Value: language},
+		telemetry.Attribute{Key: "accelerator", Value: h.cfg.Accelerator},
+		telemetry.Attribute{Key: "alias", Value: h.cfg.Alias},
+	)
+
+	session, err := newOpusSession(h.log, h.engine, streamMetrics, language)
+	if err != nil {
+		streamMetrics.Finish(err)
+		h.log.Error("failed to start opus session", "session_id", sessionID, "error", err)
+		return
+	}
+
+	ctx := context.Background()
+	var handlerErr error
+	for {
+		frame := make([]byte, maxOpusWSFrameBytes)
+		n, err := ws.Read(frame)
+		if err != nil {
+			break
+		}
+		if ingestErr := session.ingest(ctx, frame[:n]); ingestErr != nil {
+			h.log.Error("failed to decode opus frame", "session_id", sessionID, "error", ingestErr)
+			handlerErr = ingestErr
+			break
+		}
+	}
+
+	results, err := session.finish(ctx)
+	if err != nil {
+		handlerErr = err
+	}
+	streamMetrics.Finish(handlerErr)
+
+	for _, res := range results {
+		if err := websocket.JSON.Send(ws, res); err != nil {
+			break
+		}
+	}
+}
+
+// queryValueOrDefault reads a query parameter from the websocket handshake's
+// original *http.Request, falling back to def when absent.
+func queryValueOrDefault(req *http.Request, key, def string) string {
+	if req == nil {
+		return def
+	}
+	if value := req.URL.Query().Get(key); value != "" {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"sort"
+	"strings"
+)
+
+// Attribute is a single key/value label attached to a metric data point or
+// span, mirroring the attribute model used by OpenTelemetry instruments.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// attributeSet canonicalises a slice of attributes into a stable,
+// comparable key so counters and histograms can aggregate per unique label
+// combination regardless of the order attributes were supplied in.
+type attributeSet string
+
+func newAttributeSet(attrs []Attribute) attributeSet {
+	if len(attrs) == 0 {
+		return ""
+	}
+	sorted := make([]Attribute, len(attrs))
+	copy(sorted, attrs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	var b strings.Builder
+	for i, a := range sorted {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(a.Value)
+	}
+	return attributeSet(b.String())
+}
+
+// hasAttribute reports whether attrs contains key=value.
+func hasAttribute(attrs []Attribute, key, value string) bool {
+	for _, a := range attrs {
+		if a.Key == key && a.Value == value {
+			return true
+		}
+	}
+	return false
+}
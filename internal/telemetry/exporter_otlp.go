@@ -0,0 +1,352 @@
+package telemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultOTLPPushInterval is how often OTLPExporter pushes a metrics
+// snapshot when the caller does not override it.
+const DefaultOTLPPushInterval = 15 * time.Second
+
+// OTLPCompressionGzip, OTLPCompressionZstd, and OTLPCompressionNone name the
+// values config.Config.OTLPCompression accepts.
+const (
+	OTLPCompressionGzip = "gzip"
+	OTLPCompressionZstd = "zstd"
+	OTLPCompressionNone = "none"
+)
+
+// OTLPOptions configures the transport-level details of OTLPExporter that
+// aren't part of the endpoint itself: auth/routing headers, TLS
+// verification, and request body compression.
+type OTLPOptions struct {
+	// Headers are added to every export request, typically used for
+	// collector auth (e.g. "Authorization": "Bearer ...").
+	Headers map[string]string
+	// Insecure skips TLS certificate verification against an https
+	// endpoint. It has no effect on an http endpoint.
+	Insecure bool
+	// Compression is OTLPCompressionGzip, OTLPCompressionZstd, or
+	// OTLPCompressionNone/"" (the default). OTLPCompressionZstd is not
+	// currently implemented and falls back to uncompressed with a warning,
+	// since this package deliberately avoids depending on a zstd library.
+	Compression string
+}
+
+// OTLPExporter periodically pushes MeterProvider snapshots and Tracer spans
+// to an OTLP/HTTP collector endpoint (e.g. an OpenTelemetry Collector) as
+// JSON, following the shape of OTLP's ExportMetricsServiceRequest and
+// ExportTraceServiceRequest without depending on the full OTel SDK and its
+// generated protobuf types.
+type OTLPExporter struct {
+	endpoint    string
+	provider    *MeterProvider
+	tracer      *Tracer
+	client      *http.Client
+	interval    time.Duration
+	headers     map[string]string
+	compression string
+	log         *slog.Logger
+}
+
+// NewOTLPExporter constructs an exporter that pushes provider's instruments
+// and tracer's spans to endpoint (the value of
+// NUPI_OTEL_EXPORTER_OTLP_ENDPOINT) on DefaultOTLPPushInterval. tracer may
+// be nil, in which case only metrics are pushed.
+func NewOTLPExporter(endpoint string, provider *MeterProvider, tracer *Tracer, opts OTLPOptions, logger *slog.Logger) *OTLPExporter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	log := logger.With("component", "telemetry.OTLPExporter", "endpoint", endpoint)
+
+	compression := opts.Compression
+	if compression == OTLPCompressionZstd {
+		log.Warn("otlp zstd compression is not implemented; exporting uncompressed")
+		compression = OTLPCompressionNone
+	}
+
+	transport := &http.Transport{}
+	if opts.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // operator opt-in via config.Config.OTLPInsecure
+	}
+
+	return &OTLPExporter{
+		endpoint:    endpoint,
+		provider:    provider,
+		tracer:      tracer,
+		client:      &http.Client{Timeout: 10 * time.Second, Transport: transport},
+		interval:    DefaultOTLPPushInterval,
+		headers:     opts.Headers,
+		compression: compression,
+		log:         log,
+	}
+}
+
+// Run pushes a snapshot every interval until ctx is cancelled. It is meant
+// to be run in its own goroutine from cmd/adapter/main.go.
+func (e *OTLPExporter) Run(ctx context.Context) {
+	if e == nil {
+		return
+	}
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.push(ctx); err != nil {
+				e.log.Warn("failed to export metrics", "error", err)
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) push(ctx context.Context) error {
+	if err := e.post(ctx, "/v1/metrics", e.buildMetricsRequest()); err != nil {
+		return fmt.Errorf("telemetry: export metrics: %w", err)
+	}
+	if spans := e.tracer.collect(); len(spans) > 0 {
+		if err := e.post(ctx, "/v1/traces", e.buildTraceRequest(spans)); err != nil {
+			return fmt.Errorf("telemetry: export traces: %w", err)
+		}
+	}
+	return nil
+}
+
+// post JSON-encodes payload, applies compression and the configured
+// headers, and POSTs it to path under e.endpoint.
+func (e *OTLPExporter) post(ctx context.Context, path string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	var contentEncoding string
+	if e.compression == OTLPCompressionGzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err != nil {
+			return fmt.Errorf("gzip request body: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("gzip request body: %w", err)
+		}
+		body = buf.Bytes()
+		contentEncoding = "gzip"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// otlpMetricsRequest mirrors the JSON encoding of OTLP's
+// ExportMetricsServiceRequest, scoped to the single resource/scope this
+// adapter reports under.
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name string   `json:"name"`
+	Unit string   `json:"unit,omitempty"`
+	Sum  *otlpSum `json:"sum,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	AsDouble     float64        `json:"asDouble"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpAggregationTemporalityCumulative matches
+// AGGREGATION_TEMPORALITY_CUMULATIVE in the OTLP metrics proto: each
+// exported value is the running total since the instrument was created,
+// which matches how Counter/Histogram accumulate internally.
+const otlpAggregationTemporalityCumulative = 2
+
+func (e *OTLPExporter) buildMetricsRequest() otlpMetricsRequest {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	metrics := make([]otlpMetric, 0)
+	for _, snap := range e.provider.collect() {
+		points := make([]otlpNumberDataPoint, 0, len(snap.points))
+		for _, p := range snap.points {
+			value := p.value
+			if snap.kind == instrumentHistogram && p.count > 0 {
+				value = p.value / float64(p.count)
+			}
+			points = append(points, otlpNumberDataPoint{
+				Attributes:   otlpAttributes(p.attrs),
+				TimeUnixNano: now,
+				AsDouble:     value,
+			})
+		}
+		metrics = append(metrics, otlpMetric{
+			Name: snap.name,
+			Sum: &otlpSum{
+				DataPoints:             points,
+				AggregationTemporality: otlpAggregationTemporalityCumulative,
+				IsMonotonic:            snap.kind == instrumentCounter,
+			},
+		})
+	}
+
+	return otlpMetricsRequest{
+		ResourceMetrics: []otlpResourceMetrics{
+			{
+				Resource: otlpResource{Attributes: []otlpKeyValue{
+					{Key: "service.name", Value: otlpAnyValue{StringValue: "plugin-stt-local-whisper"}},
+				}},
+				ScopeMetrics: []otlpScopeMetrics{
+					{Scope: otlpScope{Name: meterName}, Metrics: metrics},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attrs []Attribute) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		out = append(out, otlpKeyValue{Key: a.Key, Value: otlpAnyValue{StringValue: a.Value}})
+	}
+	return out
+}
+
+// otlpTraceRequest mirrors the JSON encoding of OTLP's
+// ExportTraceServiceRequest, scoped to the single resource/scope this
+// adapter reports under.
+type otlpTraceRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource    `json:"resource"`
+	ScopeSpans []otlpScopeSpan `json:"scopeSpans"`
+}
+
+type otlpScopeSpan struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	Name              string         `json:"name"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// otlpStatusCodeUnset, otlpStatusCodeError match STATUS_CODE_UNSET and
+// STATUS_CODE_ERROR in the OTLP trace proto; this exporter never sets
+// STATUS_CODE_OK since Tracer has no notion of an explicitly-successful
+// status distinct from "unset".
+const (
+	otlpStatusCodeUnset = 0
+	otlpStatusCodeError = 2
+)
+
+func (e *OTLPExporter) buildTraceRequest(spans []SpanRecord) otlpTraceRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		status := otlpStatus{Code: otlpStatusCodeUnset}
+		if s.Err != nil {
+			status = otlpStatus{Code: otlpStatusCodeError, Message: s.Err.Error()}
+		}
+		out = append(out, otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			Name:              s.Name,
+			StartTimeUnixNano: fmt.Sprintf("%d", s.Start.UnixNano()),
+			EndTimeUnixNano:   fmt.Sprintf("%d", s.Start.Add(s.Duration).UnixNano()),
+			Attributes:        otlpAttributes(s.Attrs),
+			Status:            status,
+		})
+	}
+
+	return otlpTraceRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{Attributes: []otlpKeyValue{
+					{Key: "service.name", Value: otlpAnyValue{StringValue: "plugin-stt-local-whisper"}},
+				}},
+				ScopeSpans: []otlpScopeSpan{
+					{Scope: otlpScope{Name: meterName}, Spans: out},
+				},
+			},
+		},
+	}
+}
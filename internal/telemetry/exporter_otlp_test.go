@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// collectedOTLPRequests is an in-memory stand-in for an OTLP/HTTP collector,
+// recording every JSON body posted to it by path.
+type collectedOTLPRequests struct {
+	mu       sync.Mutex
+	byPath   map[string][]json.RawMessage
+	headers  http.Header
+	encoding string
+}
+
+func newOTLPTestCollector() (*httptest.Server, *collectedOTLPRequests) {
+	collected := &collectedOTLPRequests{byPath: make(map[string][]json.RawMessage)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer gz.Close()
+			body = gz
+		}
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		collected.mu.Lock()
+		collected.byPath[r.URL.Path] = append(collected.byPath[r.URL.Path], json.RawMessage(raw))
+		collected.headers = r.Header.Clone()
+		collected.encoding = r.Header.Get("Content-Encoding")
+		collected.mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	return srv, collected
+}
+
+func TestOTLPExporterPushesMetricsAndTraces(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, collected := newOTLPTestCollector()
+	defer srv.Close()
+
+	provider := NewMeterProvider()
+	meter := provider.Meter(meterName)
+	meter.Counter("stt.segments", "Audio segments received per stream.").Add(1, Attribute{Key: "model_variant", Value: "base"})
+
+	tracer := NewTracer(logger)
+	_, span := tracer.Start(context.Background(), "TranscribeSegment", Attribute{Key: "session_id", Value: "s1"})
+	span.End(nil)
+
+	exporter := NewOTLPExporter(srv.URL, provider, tracer, OTLPOptions{
+		Headers: map[string]string{"Authorization": "Bearer test-token"},
+	}, logger)
+
+	if err := exporter.push(context.Background()); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	collected.mu.Lock()
+	if len(collected.byPath["/v1/metrics"]) != 1 {
+		collected.mu.Unlock()
+		t.Fatalf("expected one metrics request, got %d", len(collected.byPath["/v1/metrics"]))
+	}
+	var metricsReq otlpMetricsRequest
+	if err := json.Unmarshal(collected.byPath["/v1/metrics"][0], &metricsReq); err != nil {
+		collected.mu.Unlock()
+		t.Fatalf("decode metrics request: %v", err)
+	}
+	if len(metricsReq.ResourceMetrics) == 0 || len(metricsReq.ResourceMetrics[0].ScopeMetrics[0].Metrics) == 0 {
+		collected.mu.Unlock()
+		t.Fatalf("expected at least one metric data point, got %+v", metricsReq)
+	}
+
+	if len(collected.byPath["/v1/traces"]) != 1 {
+		collected.mu.Unlock()
+		t.Fatalf("expected one traces request, got %d", len(collected.byPath["/v1/traces"]))
+	}
+	var traceReq otlpTraceRequest
+	if err := json.Unmarshal(collected.byPath["/v1/traces"][0], &traceReq); err != nil {
+		collected.mu.Unlock()
+		t.Fatalf("decode traces request: %v", err)
+	}
+	spans := traceReq.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 || spans[0].Name != "TranscribeSegment" {
+		collected.mu.Unlock()
+		t.Fatalf("expected one TranscribeSegment span, got %+v", spans)
+	}
+
+	authHeader := collected.headers.Get("Authorization")
+	collected.mu.Unlock()
+	if authHeader != "Bearer test-token" {
+		t.Fatalf("expected auth header to be forwarded, got %q", authHeader)
+	}
+
+	// A second push with nothing new buffered on the tracer should only
+	// export metrics, not an empty traces request.
+	collected.mu.Lock()
+	collected.byPath = make(map[string][]json.RawMessage)
+	collected.mu.Unlock()
+
+	if err := exporter.push(context.Background()); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	if len(collected.byPath["/v1/traces"]) != 0 {
+		t.Fatalf("expected no traces request when no spans are buffered, got %d", len(collected.byPath["/v1/traces"]))
+	}
+}
+
+func TestOTLPExporterCompressesGzip(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, collected := newOTLPTestCollector()
+	defer srv.Close()
+
+	provider := NewMeterProvider()
+	exporter := NewOTLPExporter(srv.URL, provider, nil, OTLPOptions{Compression: OTLPCompressionGzip}, logger)
+
+	if err := exporter.push(context.Background()); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	if collected.encoding != "gzip" {
+		t.Fatalf("expected gzip content-encoding, got %q", collected.encoding)
+	}
+}
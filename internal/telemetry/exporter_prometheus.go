@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusHandler returns an http.Handler that renders provider's current
+// instruments in Prometheus text exposition format. It is the adapter's
+// fallback telemetry surface, mounted next to the gRPC listener, when no
+// OTLP endpoint is configured.
+func PrometheusHandler(provider *MeterProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		snapshots := provider.collect()
+		sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].name < snapshots[j].name })
+
+		for _, snap := range snapshots {
+			metricName := prometheusName(snap.name)
+			fmt.Fprintf(w, "# HELP %s %s\n", metricName, snap.help)
+			fmt.Fprintf(w, "# TYPE %s %s\n", metricName, prometheusType(snap.kind))
+
+			points := snap.points
+			sort.Slice(points, func(i, j int) bool { return newAttributeSet(points[i].attrs) < newAttributeSet(points[j].attrs) })
+
+			for _, p := range points {
+				switch snap.kind {
+				case instrumentHistogram:
+					fmt.Fprintf(w, "%s_sum%s %s\n", metricName, formatLabels(p.attrs), formatValue(p.value))
+					fmt.Fprintf(w, "%s_count%s %d\n", metricName, formatLabels(p.attrs), p.count)
+				default:
+					fmt.Fprintf(w, "%s%s %s\n", metricName, formatLabels(p.attrs), formatValue(p.value))
+				}
+			}
+		}
+	})
+}
+
+// prometheusName rewrites an OTel-style dotted instrument name (e.g.
+// "stt.segments") into Prometheus's underscore convention.
+func prometheusName(name string) string {
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+func prometheusType(kind instrumentKind) string {
+	switch kind {
+	case instrumentHistogram:
+		return "summary"
+	default:
+		return "counter"
+	}
+}
+
+func formatLabels(attrs []Attribute) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	sorted := make([]Attribute, len(attrs))
+	copy(sorted, attrs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, a := range sorted {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(a.Key)
+		b.WriteString(`="`)
+		b.WriteString(strings.ReplaceAll(a.Value, `"`, `\"`))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
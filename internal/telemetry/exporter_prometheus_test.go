@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusHandlerRendersCounterAndHistogram(t *testing.T) {
+	provider := NewMeterProvider()
+	meter := provider.Meter(meterName)
+	meter.Counter("stt.segments", "Audio segments received per stream.").Add(3, Attribute{Key: "model_variant", Value: "base"})
+	meter.Histogram("stt.rtf", "Real-time factor.").Record(0.5, Attribute{Key: "model_variant", Value: "base"})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	PrometheusHandler(provider).ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `stt_segments{model_variant="base"} 3`) {
+		t.Fatalf("expected counter line in output, got:\n%s", body)
+	}
+	if !strings.Contains(body, `stt_rtf_sum{model_variant="base"} 0.5`) {
+		t.Fatalf("expected histogram sum line in output, got:\n%s", body)
+	}
+	if !strings.Contains(body, `stt_rtf_count{model_variant="base"} 1`) {
+		t.Fatalf("expected histogram count line in output, got:\n%s", body)
+	}
+}
@@ -0,0 +1,265 @@
+package telemetry
+
+import "sync"
+
+// dataPoint is a single (attributes, value) observation collected from a
+// Counter or Histogram, read by exporters (exporter_prometheus.go,
+// exporter_otlp.go).
+type dataPoint struct {
+	attrs []Attribute
+	value float64
+	count uint64
+}
+
+// instrumentKind distinguishes Prometheus/OTLP exposition shapes.
+type instrumentKind string
+
+const (
+	instrumentCounter   instrumentKind = "counter"
+	instrumentHistogram instrumentKind = "histogram"
+)
+
+// instrumentSnapshot is an exporter-facing view of one registered
+// instrument's current data points.
+type instrumentSnapshot struct {
+	name   string
+	help   string
+	kind   instrumentKind
+	points []dataPoint
+}
+
+// Counter is a monotonically increasing OTel-style instrument. Values are
+// aggregated per unique attribute set so exporters can emit labeled time
+// series instead of a single global total.
+type Counter struct {
+	name string
+	help string
+
+	mu     sync.Mutex
+	totals map[attributeSet]float64
+	labels map[attributeSet][]Attribute
+}
+
+func newCounter(name, help string) *Counter {
+	return &Counter{
+		name:   name,
+		help:   help,
+		totals: make(map[attributeSet]float64),
+		labels: make(map[attributeSet][]Attribute),
+	}
+}
+
+// Add increments the counter by delta for the data point identified by attrs.
+func (c *Counter) Add(delta float64, attrs ...Attribute) {
+	if c == nil {
+		return
+	}
+	key := newAttributeSet(attrs)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.totals[key] += delta
+	if _, ok := c.labels[key]; !ok {
+		c.labels[key] = attrs
+	}
+}
+
+// Sum returns the aggregate value across every attribute set recorded so far.
+func (c *Counter) Sum() float64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sum float64
+	for _, v := range c.totals {
+		sum += v
+	}
+	return sum
+}
+
+// SumAttr returns the aggregate value across data points whose attribute
+// set includes key=value, letting callers derive a labeled subtotal (e.g.
+// Recorder.Snapshot deriving TotalFinalTranscripts from stt.transcripts).
+func (c *Counter) SumAttr(key, value string) float64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sum float64
+	for attrKey, total := range c.totals {
+		if hasAttribute(c.labels[attrKey], key, value) {
+			sum += total
+		}
+	}
+	return sum
+}
+
+func (c *Counter) snapshot() instrumentSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	points := make([]dataPoint, 0, len(c.totals))
+	for key, value := range c.totals {
+		points = append(points, dataPoint{attrs: c.labels[key], value: value})
+	}
+	return instrumentSnapshot{name: c.name, help: c.help, kind: instrumentCounter, points: points}
+}
+
+// Histogram records a distribution of observed values (durations, ratios),
+// modeled on OTel's synchronous Histogram instrument. It tracks a running
+// count/sum per attribute set rather than fixed buckets, which is enough to
+// expose an average through Prometheus/OTLP without vendoring a full
+// bucketing implementation.
+type Histogram struct {
+	name string
+	help string
+
+	mu     sync.Mutex
+	counts map[attributeSet]uint64
+	sums   map[attributeSet]float64
+	labels map[attributeSet][]Attribute
+}
+
+func newHistogram(name, help string) *Histogram {
+	return &Histogram{
+		name:   name,
+		help:   help,
+		counts: make(map[attributeSet]uint64),
+		sums:   make(map[attributeSet]float64),
+		labels: make(map[attributeSet][]Attribute),
+	}
+}
+
+// Record adds a single observation to the histogram for the data point
+// identified by attrs.
+func (h *Histogram) Record(value float64, attrs ...Attribute) {
+	if h == nil {
+		return
+	}
+	key := newAttributeSet(attrs)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[key]++
+	h.sums[key] += value
+	if _, ok := h.labels[key]; !ok {
+		h.labels[key] = attrs
+	}
+}
+
+func (h *Histogram) snapshot() instrumentSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	points := make([]dataPoint, 0, len(h.sums))
+	for key, sum := range h.sums {
+		points = append(points, dataPoint{attrs: h.labels[key], value: sum, count: h.counts[key]})
+	}
+	return instrumentSnapshot{name: h.name, help: h.help, kind: instrumentHistogram, points: points}
+}
+
+// Meter is a named group of instruments, mirroring otel/metric.Meter.
+// Instruments are created lazily and cached by name so repeated calls with
+// the same name return the same instrument.
+type Meter struct {
+	name string
+
+	mu         sync.Mutex
+	counters   map[string]*Counter
+	histograms map[string]*Histogram
+}
+
+func newMeter(name string) *Meter {
+	return &Meter{
+		name:       name,
+		counters:   make(map[string]*Counter),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Counter returns the named counter instrument, creating it on first use.
+func (m *Meter) Counter(name, help string) *Counter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.counters[name]; ok {
+		return c
+	}
+	c := newCounter(name, help)
+	m.counters[name] = c
+	return c
+}
+
+// Histogram returns the named histogram instrument, creating it on first use.
+func (m *Meter) Histogram(name, help string) *Histogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok := m.histograms[name]; ok {
+		return h
+	}
+	h := newHistogram(name, help)
+	m.histograms[name] = h
+	return h
+}
+
+func (m *Meter) snapshot() []instrumentSnapshot {
+	m.mu.Lock()
+	counters := make([]*Counter, 0, len(m.counters))
+	for _, c := range m.counters {
+		counters = append(counters, c)
+	}
+	histograms := make([]*Histogram, 0, len(m.histograms))
+	for _, h := range m.histograms {
+		histograms = append(histograms, h)
+	}
+	m.mu.Unlock()
+
+	out := make([]instrumentSnapshot, 0, len(counters)+len(histograms))
+	for _, c := range counters {
+		out = append(out, c.snapshot())
+	}
+	for _, h := range histograms {
+		out = append(out, h.snapshot())
+	}
+	return out
+}
+
+// MeterProvider is the root of the adapter's OTel-style metrics. It owns a
+// small set of named meters and exposes their instruments to exporters
+// (exporter_prometheus.go's Handler, exporter_otlp.go's OTLPExporter)
+// without pulling in the full OpenTelemetry SDK.
+type MeterProvider struct {
+	mu     sync.Mutex
+	meters map[string]*Meter
+}
+
+// NewMeterProvider constructs an empty MeterProvider.
+func NewMeterProvider() *MeterProvider {
+	return &MeterProvider{meters: make(map[string]*Meter)}
+}
+
+// Meter returns the named meter, creating it on first use.
+func (p *MeterProvider) Meter(name string) *Meter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m, ok := p.meters[name]; ok {
+		return m
+	}
+	m := newMeter(name)
+	p.meters[name] = m
+	return m
+}
+
+// collect gathers a point-in-time snapshot of every instrument registered
+// across every meter, for exporters to render.
+func (p *MeterProvider) collect() []instrumentSnapshot {
+	p.mu.Lock()
+	meters := make([]*Meter, 0, len(p.meters))
+	for _, m := range p.meters {
+		meters = append(meters, m)
+	}
+	p.mu.Unlock()
+
+	var out []instrumentSnapshot
+	for _, m := range meters {
+		out = append(out, m.snapshot()...)
+	}
+	return out
+}
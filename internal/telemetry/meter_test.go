@@ -0,0 +1,65 @@
+package telemetry
+
+import "testing"
+
+func TestCounterAddAndSum(t *testing.T) {
+	c := newCounter("stt.segments", "test counter")
+	c.Add(2, Attribute{Key: "model_variant", Value: "base"})
+	c.Add(3, Attribute{Key: "model_variant", Value: "small"})
+
+	if got, want := c.Sum(), 5.0; got != want {
+		t.Fatalf("Sum() = %v, want %v", got, want)
+	}
+}
+
+func TestCounterSumAttr(t *testing.T) {
+	c := newCounter("stt.transcripts", "test counter")
+	c.Add(1, Attribute{Key: "final", Value: "true"})
+	c.Add(1, Attribute{Key: "final", Value: "false"})
+	c.Add(1, Attribute{Key: "final", Value: "true"})
+
+	if got, want := c.SumAttr("final", "true"), 2.0; got != want {
+		t.Fatalf("SumAttr(final, true) = %v, want %v", got, want)
+	}
+	if got, want := c.SumAttr("final", "false"), 1.0; got != want {
+		t.Fatalf("SumAttr(final, false) = %v, want %v", got, want)
+	}
+}
+
+func TestHistogramRecord(t *testing.T) {
+	h := newHistogram("stt.rtf", "test histogram")
+	h.Record(0.5)
+	h.Record(1.5)
+
+	snap := h.snapshot()
+	if len(snap.points) != 1 {
+		t.Fatalf("expected a single aggregated data point, got %d", len(snap.points))
+	}
+	if got, want := snap.points[0].value, 2.0; got != want {
+		t.Fatalf("unexpected sum: got %v, want %v", got, want)
+	}
+	if got, want := snap.points[0].count, uint64(2); got != want {
+		t.Fatalf("unexpected count: got %v, want %v", got, want)
+	}
+}
+
+func TestMeterProviderCollect(t *testing.T) {
+	provider := NewMeterProvider()
+	meter := provider.Meter("test")
+	meter.Counter("stt.segments", "help").Add(1)
+	meter.Histogram("stt.rtf", "help").Record(0.3)
+
+	snapshots := provider.collect()
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 instrument snapshots, got %d", len(snapshots))
+	}
+}
+
+func TestMeterReturnsSameInstrumentByName(t *testing.T) {
+	meter := newMeter("test")
+	a := meter.Counter("stt.segments", "help")
+	b := meter.Counter("stt.segments", "help")
+	if a != b {
+		t.Fatalf("expected repeated Counter() calls to return the same instrument")
+	}
+}
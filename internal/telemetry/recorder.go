@@ -2,22 +2,44 @@ package telemetry
 
 import (
 	"log/slog"
+	"strconv"
 	"sync/atomic"
 	"time"
 	"unicode/utf8"
 )
 
-// Recorder tracks adapter-level telemetry that can be forwarded to the daemon/event bus.
+// meterName identifies the adapter's OTel meter, following the
+// instrumentation-scope-name convention of using the owning package path.
+const meterName = "github.com/nupi-ai/plugin-stt-local-whisper/internal/telemetry"
+
+// pcmBytesPerMs is the byte rate of 16-bit mono PCM sampled at 16kHz, the
+// adapter's fixed ingress format, used to translate segment byte counts
+// into audio duration for the stt.rtf real-time-factor metric.
+const pcmBytesPerMs = 2 * 16000 / 1000
+
+// Recorder tracks adapter-level telemetry that can be forwarded to the
+// daemon/event bus. It is implemented on top of an OTel-style MeterProvider
+// (see meter.go) so the in-memory Snapshot API and external exporters
+// (Prometheus /metrics, OTLP push) read from the same counters.
 type Recorder struct {
-	log *slog.Logger
+	log      *slog.Logger
+	provider *MeterProvider
+	tracer   *Tracer
+
+	segments       *Counter
+	bytes          *Counter
+	transcripts    *Counter
+	rtf            *Histogram
+	segmentLatency *Histogram
+	streamDuration *Histogram
+	poolLookups    *Counter
+	voiceActivity  *Counter
+	modelLoads     *Counter
+	modelEvictions *Counter
 
-	totalStreams          atomic.Uint64
-	activeStreams         atomic.Int64
-	totalSegments         atomic.Uint64
-	totalBytes            atomic.Uint64
-	totalTranscripts      atomic.Uint64
-	totalFinalTranscripts atomic.Uint64
-	totalFlushes          atomic.Uint64
+	totalStreams  atomic.Uint64
+	activeStreams atomic.Int64
+	totalFlushes  atomic.Uint64
 }
 
 // Snapshot captures cumulative metrics recorded so far.
@@ -31,16 +53,51 @@ type Snapshot struct {
 	TotalFlushes          uint64
 }
 
-// NewRecorder constructs a Recorder using the provided logger.
+// NewRecorder constructs a Recorder using the provided logger. It owns its
+// own MeterProvider; use MeterProvider to mount a Prometheus handler or
+// attach an OTLP exporter.
 func NewRecorder(logger *slog.Logger) *Recorder {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	provider := NewMeterProvider()
+	meter := provider.Meter(meterName)
 	return &Recorder{
-		log: logger.With("component", "telemetry.Recorder"),
+		log:            logger.With("component", "telemetry.Recorder"),
+		provider:       provider,
+		tracer:         NewTracer(logger),
+		segments:       meter.Counter("stt.segments", "Audio segments received per stream."),
+		bytes:          meter.Counter("stt.bytes", "Audio bytes received per stream."),
+		transcripts:    meter.Counter("stt.transcripts", "Transcripts emitted, labeled by final=true|false."),
+		rtf:            meter.Histogram("stt.rtf", "Decode time divided by audio duration (real-time factor)."),
+		segmentLatency: meter.Histogram("stt.segment.latency", "Wall-clock engine latency for a single segment decode, in seconds."),
+		streamDuration: meter.Histogram("stt.stream.duration", "Wall-clock duration of a transcription stream, in seconds."),
+		poolLookups:    meter.Counter("stt.pool.lookups", "EnginePool dispatches, labeled by model_variant and hit=true|false."),
+		voiceActivity:  meter.Counter("stt.voice_activity.ms", "Audio duration gated by internal/vad, labeled by is_speech=true|false."),
+		modelLoads:     meter.Counter("stt.pool.loads", "EnginePool variants loaded, labeled by model_variant, so operators can see which are actually in use."),
+		modelEvictions: meter.Counter("stt.pool.evictions", "EnginePool variants evicted to stay within MaxResidentModels/MaxResidentBytes, labeled by model_variant."),
 	}
 }
 
+// MeterProvider exposes the recorder's underlying OTel-style meter provider
+// so callers can mount a Prometheus handler (exporter_prometheus.go) or
+// attach an OTLP exporter (exporter_otlp.go).
+func (r *Recorder) MeterProvider() *MeterProvider {
+	if r == nil {
+		return nil
+	}
+	return r.provider
+}
+
+// Tracer exposes the recorder's span tracer so callers (internal/server) can
+// wrap engine calls in spans without constructing their own Tracer.
+func (r *Recorder) Tracer() *Tracer {
+	if r == nil {
+		return nil
+	}
+	return r.tracer
+}
+
 // Snapshot returns an immutable view of the recorder totals.
 func (r *Recorder) Snapshot() Snapshot {
 	if r == nil {
@@ -49,14 +106,45 @@ func (r *Recorder) Snapshot() Snapshot {
 	return Snapshot{
 		TotalStreams:          r.totalStreams.Load(),
 		ActiveStreams:         r.activeStreams.Load(),
-		TotalSegments:         r.totalSegments.Load(),
-		TotalBytes:            r.totalBytes.Load(),
-		TotalTranscripts:      r.totalTranscripts.Load(),
-		TotalFinalTranscripts: r.totalFinalTranscripts.Load(),
+		TotalSegments:         uint64(r.segments.Sum()),
+		TotalBytes:            uint64(r.bytes.Sum()),
+		TotalTranscripts:      uint64(r.transcripts.Sum()),
+		TotalFinalTranscripts: uint64(r.transcripts.SumAttr("final", "true")),
 		TotalFlushes:          r.totalFlushes.Load(),
 	}
 }
 
+// RecordPoolLookup tags an EnginePool dispatch with the variant it resolved
+// to and whether that variant's engine was already resident (hit) or had to
+// be loaded, possibly evicting another entry first (miss).
+func (r *Recorder) RecordPoolLookup(variant string, hit bool) {
+	if r == nil {
+		return
+	}
+	r.poolLookups.Add(1,
+		Attribute{Key: "model_variant", Value: variant},
+		Attribute{Key: "hit", Value: strconv.FormatBool(hit)},
+	)
+}
+
+// RecordModelLoaded tags variant as newly loaded into an EnginePool's
+// resident set, on either a pool miss or an admin-triggered warm load.
+func (r *Recorder) RecordModelLoaded(variant string) {
+	if r == nil {
+		return
+	}
+	r.modelLoads.Add(1, Attribute{Key: "model_variant", Value: variant})
+}
+
+// RecordModelEvicted tags variant as dropped from an EnginePool's resident
+// set, whether by LRU pressure or an admin-triggered unload.
+func (r *Recorder) RecordModelEvicted(variant string) {
+	if r == nil {
+		return
+	}
+	r.modelEvictions.Add(1, Attribute{Key: "model_variant", Value: variant})
+}
+
 // StreamMetrics accumulates statistics for a single transcription stream.
 type StreamMetrics struct {
 	recorder *Recorder
@@ -65,19 +153,27 @@ type StreamMetrics struct {
 	sessionID string
 	streamID  string
 	metadata  map[string]string
+	// attrs labels stt.segments/stt.bytes/stt.transcripts/stt.rtf data
+	// points (model_variant, language, accelerator). sessionID is
+	// deliberately excluded to keep metric cardinality bounded; it is
+	// still attached to spans, where per-session attribution is useful.
+	attrs []Attribute
 
-	started          time.Time
-	segments         int
-	bytes            int
-	transcripts      int
-	finalTranscripts int
-	flushes          int
-	lastSequence     uint64
-	closed           atomic.Bool
+	started            time.Time
+	segments           int
+	bytes              int
+	transcripts        int
+	finalTranscripts   int
+	flushes            int
+	lastSequence       uint64
+	lastInferenceBytes int
+	closed             atomic.Bool
 }
 
 // StartStream initialises a StreamMetrics instance bound to the recorder.
-func (r *Recorder) StartStream(sessionID, streamID string, metadata map[string]string) *StreamMetrics {
+// attrs are attached to every metric data point recorded for this stream
+// (e.g. model_variant, language, accelerator).
+func (r *Recorder) StartStream(sessionID, streamID string, metadata map[string]string, attrs ...Attribute) *StreamMetrics {
 	if r == nil {
 		return nil
 	}
@@ -102,6 +198,7 @@ func (r *Recorder) StartStream(sessionID, streamID string, metadata map[string]s
 		sessionID: sessionID,
 		streamID:  streamID,
 		metadata:  clonedMetadata,
+		attrs:     attrs,
 
 		started: time.Now(),
 	}
@@ -115,8 +212,8 @@ func (s *StreamMetrics) RecordSegment(sequence uint64, size int, final bool) {
 	s.segments++
 	s.bytes += size
 	s.lastSequence = sequence
-	s.recorder.totalSegments.Add(1)
-	s.recorder.totalBytes.Add(uint64(size))
+	s.recorder.segments.Add(1, s.attrs...)
+	s.recorder.bytes.Add(float64(size), s.attrs...)
 
 	s.log.Debug("segment received",
 		"sequence", sequence,
@@ -133,9 +230,8 @@ func (s *StreamMetrics) RecordTranscript(sequence uint64, text string, final boo
 	s.transcripts++
 	if final {
 		s.finalTranscripts++
-		s.recorder.totalFinalTranscripts.Add(1)
 	}
-	s.recorder.totalTranscripts.Add(1)
+	s.recorder.transcripts.Add(1, s.withFinal(final)...)
 
 	s.log.Debug("transcript emitted",
 		"sequence", sequence,
@@ -145,6 +241,29 @@ func (s *StreamMetrics) RecordTranscript(sequence uint64, text string, final boo
 	)
 }
 
+// withFinal returns s.attrs with a final=true|false label appended, used to
+// tag stt.transcripts data points as the request's OTel instrument set
+// requires.
+func (s *StreamMetrics) withFinal(final bool) []Attribute {
+	out := make([]Attribute, 0, len(s.attrs)+1)
+	out = append(out, s.attrs...)
+	return append(out, Attribute{Key: "final", Value: strconv.FormatBool(final)})
+}
+
+// RecordVoiceActivity tags durationMs of buffered audio as speech or
+// silence, as internal/vad's gate classified it. Callers record an
+// is_speech=false data point for audio the gate withheld from
+// engine.TranscribeSegment entirely, so downstream consumers can measure
+// ASR-free time without inferring it from gaps between transcripts.
+func (s *StreamMetrics) RecordVoiceActivity(isSpeech bool, durationMs int) {
+	if s == nil || durationMs <= 0 {
+		return
+	}
+	s.recorder.voiceActivity.Add(float64(durationMs),
+		Attribute{Key: "is_speech", Value: strconv.FormatBool(isSpeech)},
+	)
+}
+
 // RecordFlush increments counters for a stream flush event.
 func (s *StreamMetrics) RecordFlush() {
 	if s == nil {
@@ -154,6 +273,36 @@ func (s *StreamMetrics) RecordFlush() {
 	s.recorder.totalFlushes.Add(1)
 }
 
+// RecordInferenceDuration records how long the engine took to decode the
+// audio received since the previous call to RecordSegment/RecordInferenceDuration.
+// It emits two observations: stt.segment.latency, the raw engine wall-clock
+// time for that decode, and stt.rtf, that same time divided by the duration
+// of audio it decoded (real-time factor).
+func (s *StreamMetrics) RecordInferenceDuration(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.recorder.segmentLatency.Record(d.Seconds(), s.attrs...)
+
+	audioBytes := s.bytes - s.lastInferenceBytes
+	s.lastInferenceBytes = s.bytes
+	if audioBytes <= 0 {
+		return
+	}
+	audioMs := audioBytes / pcmBytesPerMs
+	if audioMs <= 0 {
+		return
+	}
+	rtf := d.Seconds() / (float64(audioMs) / 1000.0)
+	s.recorder.rtf.Record(rtf, s.attrs...)
+
+	s.log.Debug("inference completed",
+		"duration_ms", d.Milliseconds(),
+		"audio_ms", audioMs,
+		"rtf", rtf,
+	)
+}
+
 // Finish logs a summary and updates active stream counters.
 func (s *StreamMetrics) Finish(err error) {
 	if s == nil {
@@ -166,6 +315,8 @@ func (s *StreamMetrics) Finish(err error) {
 	defer s.recorder.activeStreams.Add(-1)
 
 	duration := time.Since(s.started)
+	s.recorder.streamDuration.Record(duration.Seconds(), s.attrs...)
+
 	args := []any{
 		"duration_ms", duration.Milliseconds(),
 		"segments", s.segments,
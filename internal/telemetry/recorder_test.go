@@ -56,6 +56,40 @@ func TestRecorderSnapshot(t *testing.T) {
 	}
 }
 
+func TestRecorderMeterProviderExposesOTelInstruments(t *testing.T) {
+	recorder := NewRecorder(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	stream := recorder.StartStream("session-1", "mic", nil,
+		Attribute{Key: "model_variant", Value: "base"},
+		Attribute{Key: "language", Value: "en"},
+		Attribute{Key: "accelerator", Value: "cpu"},
+	)
+
+	stream.RecordSegment(1, 3200, true) // 100ms of 16kHz mono PCM16LE
+	stream.RecordInferenceDuration(50 * time.Millisecond)
+	stream.RecordTranscript(1, "hello world", true)
+	stream.Finish(nil)
+
+	meter := recorder.MeterProvider().Meter(meterName)
+	if got, want := meter.Counter("stt.segments", "").Sum(), 1.0; got != want {
+		t.Fatalf("stt.segments sum = %v, want %v", got, want)
+	}
+	if got, want := meter.Counter("stt.bytes", "").Sum(), 3200.0; got != want {
+		t.Fatalf("stt.bytes sum = %v, want %v", got, want)
+	}
+	if got, want := meter.Counter("stt.transcripts", "").SumAttr("final", "true"), 1.0; got != want {
+		t.Fatalf("stt.transcripts{final=true} sum = %v, want %v", got, want)
+	}
+	if meter.Histogram("stt.rtf", "").snapshot().points[0].value <= 0 {
+		t.Fatalf("expected a positive stt.rtf observation")
+	}
+	if meter.Histogram("stt.stream.duration", "").snapshot().points[0].count != 1 {
+		t.Fatalf("expected one stt.stream.duration observation")
+	}
+	if got, want := meter.Histogram("stt.segment.latency", "").snapshot().points[0].value, 0.05; got != want {
+		t.Fatalf("stt.segment.latency = %v, want %v", got, want)
+	}
+}
+
 func TestStreamFinishWithError(t *testing.T) {
 	recorder := NewRecorder(slog.New(slog.NewTextHandler(io.Discard, nil)))
 	stream := recorder.StartStream("s", "mic", nil)
@@ -74,3 +108,19 @@ func TestStreamFinishWithError(t *testing.T) {
 		t.Fatalf("unexpected flushes: %d", snapshot.TotalFlushes)
 	}
 }
+
+func TestRecordPoolLookup(t *testing.T) {
+	recorder := NewRecorder(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	recorder.RecordPoolLookup("base", false)
+	recorder.RecordPoolLookup("base", true)
+	recorder.RecordPoolLookup("tiny.en", false)
+
+	meter := recorder.MeterProvider().Meter(meterName)
+	lookups := meter.Counter("stt.pool.lookups", "")
+	if got, want := lookups.SumAttr("hit", "true"), 1.0; got != want {
+		t.Fatalf("stt.pool.lookups{hit=true} sum = %v, want %v", got, want)
+	}
+	if got, want := lookups.SumAttr("hit", "false"), 2.0; got != want {
+		t.Fatalf("stt.pool.lookups{hit=false} sum = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxBufferedSpans bounds how many completed spans Tracer retains between
+// OTLPExporter pushes, dropping the oldest once full so a slow or
+// unconfigured exporter cannot grow this unboundedly.
+const maxBufferedSpans = 4096
+
+// Tracer emits spans around engine calls so operators can correlate slow or
+// failing segments with session/model/accelerator attributes in their trace
+// backend. It logs span completion structurally and also buffers finished
+// spans so OTLPExporter can forward them to a collector when an OTLP
+// endpoint is configured (see Tracer.collect).
+type Tracer struct {
+	log *slog.Logger
+
+	mu    sync.Mutex
+	spans []SpanRecord
+}
+
+// NewTracer constructs a Tracer using the provided logger.
+func NewTracer(logger *slog.Logger) *Tracer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Tracer{log: logger.With("component", "telemetry.Tracer")}
+}
+
+// SpanRecord is a finished span as OTLPExporter reads it off Tracer.collect.
+type SpanRecord struct {
+	Name     string
+	TraceID  string
+	SpanID   string
+	Start    time.Time
+	Duration time.Duration
+	Attrs    []Attribute
+	Err      error
+}
+
+// collect drains and returns every span buffered since the last call.
+func (t *Tracer) collect() []SpanRecord {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.spans) == 0 {
+		return nil
+	}
+	spans := t.spans
+	t.spans = nil
+	return spans
+}
+
+func (t *Tracer) record(rec SpanRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.spans) >= maxBufferedSpans {
+		t.spans = t.spans[1:]
+	}
+	t.spans = append(t.spans, rec)
+}
+
+// Span represents a single traced operation, such as one TranscribeSegment
+// or Flush call.
+type Span struct {
+	tracer  *Tracer
+	name    string
+	traceID string
+	spanID  string
+	started time.Time
+	attrs   []Attribute
+}
+
+// Start begins a span named name with the given attributes attached. The
+// returned context currently has no propagation behaviour of its own; it is
+// accepted so callers can thread it through engine calls that may start
+// child spans in the future.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	return ctx, &Span{
+		tracer:  t,
+		name:    name,
+		traceID: randomID(16),
+		spanID:  randomID(8),
+		started: time.Now(),
+		attrs:   attrs,
+	}
+}
+
+// End completes the span, logging its duration and attributes; err, when
+// non-nil, is recorded as the span's error status.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	duration := time.Since(s.started)
+	s.tracer.record(SpanRecord{
+		Name:     s.name,
+		TraceID:  s.traceID,
+		SpanID:   s.spanID,
+		Start:    s.started,
+		Duration: duration,
+		Attrs:    s.attrs,
+		Err:      err,
+	})
+
+	args := make([]any, 0, 6+2*len(s.attrs)+2)
+	args = append(args,
+		"span", s.name,
+		"trace_id", s.traceID,
+		"span_id", s.spanID,
+		"duration_ms", duration.Milliseconds(),
+	)
+	for _, a := range s.attrs {
+		args = append(args, a.Key, a.Value)
+	}
+	if err != nil {
+		args = append(args, "error", err)
+		s.tracer.log.Error("span completed with error", args...)
+		return
+	}
+	s.tracer.log.Debug("span completed", args...)
+}
+
+// randomID returns n random bytes hex-encoded, used as a trace/span ID.
+// Generation failures fall back to a zeroed ID rather than propagating an
+// error through span bookkeeping.
+func randomID(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return hex.EncodeToString(buf)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,106 @@
+package vad
+
+import "math"
+
+const (
+	// energyFrameMs is the window EnergyDetector evaluates RMS energy and
+	// zero-crossing rate over, matching internal/engine/segmenter's frameMs.
+	energyFrameMs      = 20
+	energyFrameSamples = SampleRate * energyFrameMs / 1000
+
+	// defaultEnergyThreshold is the RMS amplitude (0..1 of full scale)
+	// above which a frame is classified as possibly speech.
+	defaultEnergyThreshold = 0.02
+	// defaultZCRThreshold is the minimum zero-crossing rate a frame above
+	// defaultEnergyThreshold must also clear, to reject sustained
+	// low-frequency tones (mains hum, HVAC rumble) that carry energy but
+	// not speech's broadband crossing rate.
+	defaultZCRThreshold = 0.1
+
+	defaultEnergyMinSpeechMs  = 200
+	defaultEnergyMinSilenceMs = 300
+	defaultEnergyPreRollMs    = 200
+)
+
+// EnergyDetector is a dependency-free VAD combining RMS energy with a
+// zero-crossing-rate gate. It batch-processes a whole PCM buffer per
+// Detect call rather than the frame-at-a-time Push segmenter.Segmenter
+// uses, since vad.Detector runs ahead of (not instead of) utterance
+// segmentation.
+type EnergyDetector struct {
+	threshold    float64
+	zcrThreshold float64
+	minSpeechMs  int
+	minSilenceMs int
+	preRollMs    int
+}
+
+// NewEnergyDetector constructs an EnergyDetector. Zero fields in cfg fall
+// back to this detector's defaults.
+func NewEnergyDetector(cfg Config) *EnergyDetector {
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = defaultEnergyThreshold
+	}
+	minSpeechMs := cfg.MinSpeechMs
+	if minSpeechMs <= 0 {
+		minSpeechMs = defaultEnergyMinSpeechMs
+	}
+	minSilenceMs := cfg.MinSilenceMs
+	if minSilenceMs <= 0 {
+		minSilenceMs = defaultEnergyMinSilenceMs
+	}
+	preRollMs := cfg.PreRollMs
+	if preRollMs <= 0 {
+		preRollMs = defaultEnergyPreRollMs
+	}
+	return &EnergyDetector{
+		threshold:    threshold,
+		zcrThreshold: defaultZCRThreshold,
+		minSpeechMs:  minSpeechMs,
+		minSilenceMs: minSilenceMs,
+		preRollMs:    preRollMs,
+	}
+}
+
+// Detect implements Detector.
+func (d *EnergyDetector) Detect(pcm []int16) []Segment {
+	isSpeech := func(start, end int) bool {
+		frame := pcm[start:end]
+		return rmsAmplitude(frame) >= d.threshold && zeroCrossingRate(frame) >= d.zcrThreshold
+	}
+	return detectSegments(len(pcm), energyFrameSamples, d.minSpeechMs, d.minSilenceMs, d.preRollMs, isSpeech)
+}
+
+// Close implements Detector; EnergyDetector holds no resources to release.
+func (d *EnergyDetector) Close() error { return nil }
+
+// rmsAmplitude returns frame's root-mean-square amplitude, normalised to
+// 0..1 of full scale.
+func rmsAmplitude(frame []int16) float64 {
+	if len(frame) == 0 {
+		return 0
+	}
+	var sumSquares float64
+	for _, sample := range frame {
+		v := float64(sample) / 32768.0
+		sumSquares += v * v
+	}
+	return math.Sqrt(sumSquares / float64(len(frame)))
+}
+
+// zeroCrossingRate returns the fraction of adjacent sample pairs in frame
+// that cross zero, a cheap proxy for how broadband (speech-like) versus
+// tonal the frame's energy is.
+func zeroCrossingRate(frame []int16) float64 {
+	if len(frame) < 2 {
+		return 0
+	}
+	crossings := 0
+	for i := 1; i < len(frame); i++ {
+		if (frame[i-1] >= 0) != (frame[i] >= 0) {
+			crossings++
+		}
+	}
+	return float64(crossings) / float64(len(frame)-1)
+}
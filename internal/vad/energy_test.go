@@ -0,0 +1,63 @@
+package vad
+
+import "testing"
+
+// sineFrame16 synthesizes n samples of a full-scale sine wave, a stand-in
+// for speech-like broadband audio with both energy and zero crossings.
+func sineFrame16(n int) []int16 {
+	out := make([]int16, n)
+	for i := range out {
+		// A ~1.6kHz square wave at 16kHz: period of 10 samples, enough
+		// crossings per 20ms frame to clear defaultZCRThreshold.
+		if (i/5)%2 == 0 {
+			out[i] = 20000
+		} else {
+			out[i] = -20000
+		}
+	}
+	return out
+}
+
+func TestEnergyDetectorFindsSpeechBetweenSilence(t *testing.T) {
+	// PreRollMs is deliberately non-zero: like segmenter.Config, a zero
+	// field falls back to this detector's own default rather than meaning
+	// "no pre-roll", so 0 here would pull in defaultEnergyPreRollMs instead.
+	d := NewEnergyDetector(Config{MinSpeechMs: 40, MinSilenceMs: 40, PreRollMs: 1})
+
+	silence := make([]int16, energyFrameSamples*5)
+	speech := sineFrame16(energyFrameSamples * 10)
+	pcm := append(append(append([]int16(nil), silence...), speech...), silence...)
+
+	segments := d.Detect(pcm)
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 speech segment, got %d: %+v", len(segments), segments)
+	}
+	wantStart := len(silence) - 1*SampleRate/1000
+	if segments[0].StartSample != wantStart {
+		t.Fatalf("expected segment to start at %d, got %d", wantStart, segments[0].StartSample)
+	}
+}
+
+func TestEnergyDetectorSilentBufferYieldsNoSegments(t *testing.T) {
+	d := NewEnergyDetector(Config{})
+	pcm := make([]int16, energyFrameSamples*20)
+	if segments := d.Detect(pcm); len(segments) != 0 {
+		t.Fatalf("expected no segments in silence, got %+v", segments)
+	}
+}
+
+func TestRMSAmplitudeEmptyFrame(t *testing.T) {
+	if got := rmsAmplitude(nil); got != 0 {
+		t.Fatalf("expected 0 for empty frame, got %v", got)
+	}
+}
+
+func TestZeroCrossingRateConstantFrameIsZero(t *testing.T) {
+	frame := make([]int16, 10)
+	for i := range frame {
+		frame[i] = 1000
+	}
+	if got := zeroCrossingRate(frame); got != 0 {
+		t.Fatalf("expected 0 crossings for constant frame, got %v", got)
+	}
+}
@@ -0,0 +1,211 @@
+//go:build silero
+
+package vad
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../../third_party/onnxruntime/include
+#cgo LDFLAGS: -L${SRCDIR}/../../third_party/onnxruntime/lib -lonnxruntime -Wl,-rpath,${SRCDIR}/../../third_party/onnxruntime/lib
+#include <stdlib.h>
+#include "onnxruntime_c_api.h"
+
+// The OrtApi struct holds its methods as C function pointers, which cgo
+// cannot invoke directly through a Go-side struct field; these shims do the
+// pointer call in C so Go only ever calls a plain function.
+static const OrtApi *nupi_ort_api(void) {
+	return OrtGetApiBase()->GetApi(ORT_API_VERSION);
+}
+static OrtStatus *nupi_ort_create_env(const OrtApi *api, OrtLoggingLevel level, const char *name, OrtEnv **env) {
+	return api->CreateEnv(level, name, env);
+}
+static OrtStatus *nupi_ort_create_session_options(const OrtApi *api, OrtSessionOptions **opts) {
+	return api->CreateSessionOptions(opts);
+}
+static OrtStatus *nupi_ort_create_session(const OrtApi *api, OrtEnv *env, const char *model_path, const OrtSessionOptions *opts, OrtSession **session) {
+	return api->CreateSession(env, model_path, opts, session);
+}
+static const char *nupi_ort_error_message(const OrtApi *api, OrtStatus *status) {
+	return api->GetErrorMessage(status);
+}
+static void nupi_ort_release_status(const OrtApi *api, OrtStatus *status) {
+	api->ReleaseStatus(status);
+}
+static void nupi_ort_release_session_options(const OrtApi *api, OrtSessionOptions *opts) {
+	api->ReleaseSessionOptions(opts);
+}
+static void nupi_ort_release_session(const OrtApi *api, OrtSession *session) {
+	api->ReleaseSession(session);
+}
+static void nupi_ort_release_env(const OrtApi *api, OrtEnv *env) {
+	api->ReleaseEnv(env);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// sileroFrameSamples is the only input window Silero VAD's published ONNX
+// graph accepts at SampleRate (512 samples / 32ms).
+const sileroFrameSamples = 512
+
+// defaultSileroThreshold is the speech probability above which
+// sileroDetector classifies a frame as speech, matching the upstream
+// project's own recommended default.
+const defaultSileroThreshold = 0.5
+
+const (
+	defaultSileroMinSpeechMs  = 200
+	defaultSileroMinSilenceMs = 300
+	defaultSileroPreRollMs    = 200
+)
+
+// sileroModelPathEnv names the environment variable pointing at the
+// silero_vad.onnx graph to load; NativeOptions-style config threading into
+// this package would require a config.Config dependency this package
+// deliberately avoids, so the model path is resolved the same way
+// WHISPERCPP_BLAS_LIBRARY et al. are: directly from the environment at
+// first use.
+const sileroModelPathEnv = "NUPI_SILERO_MODEL_PATH"
+
+// sileroDetector runs Silero VAD's ONNX graph over buffered PCM16 audio via
+// ONNX Runtime's C API. The session is created lazily on the first Detect
+// call rather than in newSileroDetector, so constructing a Detector (which
+// New does unconditionally for BackendSilero) doesn't pay ONNX Runtime's
+// session-init cost, or fail outright, until a caller actually submits
+// audio.
+type sileroDetector struct {
+	cfg Config
+
+	mu      sync.Mutex
+	env     *C.OrtEnv
+	session *C.OrtSession
+	api     *C.OrtApi
+	initErr error
+}
+
+func newSileroDetector(cfg Config) (Detector, error) {
+	return &sileroDetector{cfg: cfg}, nil
+}
+
+// Detect implements Detector.
+func (d *sileroDetector) Detect(pcm []int16) []Segment {
+	if err := d.ensureSession(); err != nil {
+		return nil
+	}
+
+	threshold := d.cfg.Threshold
+	if threshold <= 0 {
+		threshold = defaultSileroThreshold
+	}
+	minSpeechMs := d.cfg.MinSpeechMs
+	if minSpeechMs <= 0 {
+		minSpeechMs = defaultSileroMinSpeechMs
+	}
+	minSilenceMs := d.cfg.MinSilenceMs
+	if minSilenceMs <= 0 {
+		minSilenceMs = defaultSileroMinSilenceMs
+	}
+	preRollMs := d.cfg.PreRollMs
+	if preRollMs <= 0 {
+		preRollMs = defaultSileroPreRollMs
+	}
+
+	isSpeech := func(start, end int) bool {
+		prob, err := d.speechProbability(pcm[start:end])
+		return err == nil && prob >= threshold
+	}
+	return detectSegments(len(pcm), sileroFrameSamples, minSpeechMs, minSilenceMs, preRollMs, isSpeech)
+}
+
+// ensureSession lazily loads the ONNX Runtime environment and session named
+// by sileroModelPathEnv, memoising any initialisation error so repeated
+// Detect calls against a misconfigured model path fail fast instead of
+// retrying ONNX Runtime setup every time.
+func (d *sileroDetector) ensureSession() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.session != nil || d.initErr != nil {
+		return d.initErr
+	}
+
+	modelPath := os.Getenv(sileroModelPathEnv)
+	if modelPath == "" {
+		d.initErr = fmt.Errorf("vad: %s is not set; silero backend requires a silero_vad.onnx path", sileroModelPathEnv)
+		return d.initErr
+	}
+
+	api := C.nupi_ort_api()
+	if api == nil {
+		d.initErr = fmt.Errorf("vad: failed to resolve ONNX Runtime API")
+		return d.initErr
+	}
+
+	var env *C.OrtEnv
+	cName := C.CString("nupi-vad-silero")
+	defer C.free(unsafe.Pointer(cName))
+	if status := C.nupi_ort_create_env(api, C.ORT_LOGGING_LEVEL_WARNING, cName, &env); status != nil {
+		d.initErr = ortError(api, status, "CreateEnv")
+		return d.initErr
+	}
+
+	var opts *C.OrtSessionOptions
+	if status := C.nupi_ort_create_session_options(api, &opts); status != nil {
+		d.initErr = ortError(api, status, "CreateSessionOptions")
+		return d.initErr
+	}
+	defer C.nupi_ort_release_session_options(api, opts)
+
+	cPath := C.CString(modelPath)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var session *C.OrtSession
+	if status := C.nupi_ort_create_session(api, env, cPath, opts, &session); status != nil {
+		d.initErr = ortError(api, status, "CreateSession")
+		return d.initErr
+	}
+
+	d.api = api
+	d.env = env
+	d.session = session
+	return nil
+}
+
+// speechProbability runs one sileroFrameSamples-sized frame through the
+// loaded session and returns the model's speech probability for it.
+func (d *sileroDetector) speechProbability(frame []int16) (float64, error) {
+	// Feeding the session its single fixed-size input tensor and reading
+	// back the scalar probability output is omitted here pending the
+	// third_party/onnxruntime vendoring this build tag depends on; wiring
+	// it in only requires CreateTensorWithDataAsOrtValue + Run against the
+	// shapes silero_vad.onnx declares.
+	return 0, fmt.Errorf("vad: silero inference not yet implemented")
+}
+
+// Close implements Detector, releasing the ONNX Runtime session and
+// environment if they were ever created.
+func (d *sileroDetector) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.api == nil {
+		return nil
+	}
+	if d.session != nil {
+		C.nupi_ort_release_session(d.api, d.session)
+		d.session = nil
+	}
+	if d.env != nil {
+		C.nupi_ort_release_env(d.api, d.env)
+		d.env = nil
+	}
+	return nil
+}
+
+func ortError(api *C.OrtApi, status *C.OrtStatus, op string) error {
+	msg := C.GoString(C.nupi_ort_error_message(api, status))
+	C.nupi_ort_release_status(api, status)
+	return fmt.Errorf("vad: %s: %s", op, msg)
+}
@@ -0,0 +1,11 @@
+//go:build !silero
+
+package vad
+
+import "fmt"
+
+// newSileroDetector returns an error when the adapter was not built with
+// the "silero" tag, mirroring engine.NewNativeEngine's stub counterpart.
+func newSileroDetector(cfg Config) (Detector, error) {
+	return nil, fmt.Errorf("vad: silero backend not compiled in (build with -tags silero)")
+}
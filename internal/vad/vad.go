@@ -0,0 +1,208 @@
+// Package vad detects speech segments in buffered PCM16 mono audio so a
+// caller can gate expensive decode calls (engine.Engine.TranscribeSegment)
+// on voice activity instead of submitting every chunk that arrives,
+// regardless of which Engine backend ends up doing the decoding. It plays a
+// similar role to internal/engine/segmenter, but runs ahead of the engine as
+// a server-side front end (see internal/server) rather than wrapping one
+// specific Engine implementation.
+package vad
+
+import "fmt"
+
+// SampleRate is the PCM16 mono sample rate every Detector expects, matching
+// the adapter's fixed ingress format.
+const SampleRate = 16000
+
+const (
+	// BackendNone disables VAD gating entirely; New's Detector treats every
+	// buffer handed to Detect as one speech segment, matching the adapter's
+	// original always-decode behaviour.
+	BackendNone = "none"
+	// BackendEnergy selects EnergyDetector, a dependency-free RMS energy and
+	// zero-crossing-rate front end.
+	BackendEnergy = "energy"
+	// BackendSilero selects the Silero VAD ONNX model, loaded lazily and
+	// gated by the "silero" build tag (see silero.go / silero_stub.go).
+	BackendSilero = "silero"
+)
+
+// Segment is a contiguous span of speech Detect found in a PCM buffer,
+// expressed as sample offsets from the start of that buffer.
+type Segment struct {
+	StartSample int
+	EndSample   int
+}
+
+// DurationMs returns the segment's length in milliseconds.
+func (s Segment) DurationMs() int {
+	return (s.EndSample - s.StartSample) * 1000 / SampleRate
+}
+
+// Detector finds speech segments in a batch of PCM16 mono audio sampled at
+// SampleRate.
+type Detector interface {
+	// Detect returns the speech segments found in pcm, in sample order.
+	Detect(pcm []int16) []Segment
+	// Close releases any resources the detector holds (a loaded ONNX
+	// session, for example). Detectors that hold none may no-op.
+	Close() error
+}
+
+// Config tunes onset/offset detection, shared by every Detector
+// implementation. A zero value for any field falls back to that
+// implementation's own default.
+type Config struct {
+	// MinSpeechMs is how long audio must be classified as speech before a
+	// segment is considered to have started.
+	MinSpeechMs int
+	// MinSilenceMs is how long trailing silence must last before a segment
+	// is considered to have ended.
+	MinSilenceMs int
+	// PreRollMs is how much audio immediately preceding a detected onset is
+	// included in the segment, so the decoder isn't starved of a syllable
+	// onset detection was slow to react to.
+	PreRollMs int
+	// Threshold is the backend-specific activation threshold: an RMS
+	// amplitude (0..1 of full scale) for BackendEnergy, or a speech
+	// probability (0..1) for BackendSilero.
+	Threshold float64
+}
+
+// New constructs the Detector named by backend, mirroring
+// config.Config.VADGateBackend.
+func New(backend string, cfg Config) (Detector, error) {
+	switch backend {
+	case "", BackendNone:
+		return nopDetector{}, nil
+	case BackendEnergy:
+		return NewEnergyDetector(cfg), nil
+	case BackendSilero:
+		return newSileroDetector(cfg)
+	default:
+		return nil, fmt.Errorf("vad: unknown backend %q", backend)
+	}
+}
+
+// nopDetector treats an entire buffer as one speech segment, implementing
+// BackendNone.
+type nopDetector struct{}
+
+func (nopDetector) Detect(pcm []int16) []Segment {
+	if len(pcm) == 0 {
+		return nil
+	}
+	return []Segment{{StartSample: 0, EndSample: len(pcm)}}
+}
+
+func (nopDetector) Close() error { return nil }
+
+// SamplesFromPCM16 decodes PCM16LE mono bytes (the adapter's ingress format)
+// into the signed samples every Detector works in.
+func SamplesFromPCM16(buf []byte) []int16 {
+	n := len(buf) / 2
+	if n == 0 {
+		return nil
+	}
+	samples := make([]int16, n)
+	for i := 0; i < n; i++ {
+		samples[i] = int16(uint16(buf[2*i]) | uint16(buf[2*i+1])<<8)
+	}
+	return samples
+}
+
+// PCM16FromSamples is SamplesFromPCM16's inverse, re-encoding samples as
+// PCM16LE bytes so a caller can hand a Segment's audio back to
+// engine.Engine.TranscribeSegment, which deals in bytes rather than samples.
+func PCM16FromSamples(samples []int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		u := uint16(s)
+		buf[2*i] = byte(u)
+		buf[2*i+1] = byte(u >> 8)
+	}
+	return buf
+}
+
+// detectSegments runs a shared onset/offset/pre-roll state machine over
+// numSamples samples, classifying consecutive frameSamples-sized windows
+// with isSpeech, so EnergyDetector and SileroDetector only need to supply a
+// per-frame classifier rather than each reimplementing hysteresis and
+// pre-roll. frameSamples need not evenly divide numSamples; the final frame
+// is simply shorter.
+func detectSegments(numSamples, frameSamples, minSpeechMs, minSilenceMs, preRollMs int, isSpeech func(start, end int) bool) []Segment {
+	if numSamples <= 0 || frameSamples <= 0 {
+		return nil
+	}
+
+	minSpeechSamples := minSpeechMs * SampleRate / 1000
+	minSilenceSamples := minSilenceMs * SampleRate / 1000
+	preRollSamples := preRollMs * SampleRate / 1000
+
+	const (
+		stateSilence = iota
+		statePending
+		stateSpeech
+	)
+
+	var (
+		segments          []Segment
+		state             = stateSilence
+		speechRunSamples  int
+		silenceRunSamples int
+		pendingStart      int
+		segStartSample    int
+	)
+
+	closeSegment := func(endSample int) {
+		start := segStartSample - preRollSamples
+		if start < 0 {
+			start = 0
+		}
+		if endSample > start {
+			segments = append(segments, Segment{StartSample: start, EndSample: endSample})
+		}
+	}
+
+	for offset := 0; offset < numSamples; offset += frameSamples {
+		end := offset + frameSamples
+		if end > numSamples {
+			end = numSamples
+		}
+		frameSamplesActual := end - offset
+
+		if isSpeech(offset, end) {
+			silenceRunSamples = 0
+			switch state {
+			case stateSilence:
+				state = statePending
+				pendingStart = offset
+				speechRunSamples = frameSamplesActual
+			case statePending:
+				speechRunSamples += frameSamplesActual
+				if speechRunSamples >= minSpeechSamples {
+					state = stateSpeech
+					segStartSample = pendingStart
+				}
+			}
+			continue
+		}
+
+		speechRunSamples = 0
+		switch state {
+		case statePending:
+			state = stateSilence
+		case stateSpeech:
+			silenceRunSamples += frameSamplesActual
+			if silenceRunSamples >= minSilenceSamples {
+				closeSegment(offset + frameSamplesActual - silenceRunSamples)
+				state = stateSilence
+				silenceRunSamples = 0
+			}
+		}
+	}
+
+	if state == stateSpeech {
+		closeSegment(numSamples)
+	}
+	return segments
+}
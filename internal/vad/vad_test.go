@@ -0,0 +1,98 @@
+package vad
+
+import "testing"
+
+func TestNewNoneBackendTreatsWholeBufferAsSpeech(t *testing.T) {
+	d, err := New(BackendNone, Config{})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	pcm := make([]int16, 1000)
+	segments := d.Detect(pcm)
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	if segments[0].StartSample != 0 || segments[0].EndSample != len(pcm) {
+		t.Fatalf("expected segment spanning the whole buffer, got %+v", segments[0])
+	}
+}
+
+func TestNewEmptyBackendIsNone(t *testing.T) {
+	d, err := New("", Config{})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if _, ok := d.(nopDetector); !ok {
+		t.Fatalf("expected empty backend to resolve to nopDetector, got %T", d)
+	}
+}
+
+func TestNewUnknownBackendErrors(t *testing.T) {
+	if _, err := New("bogus", Config{}); err == nil {
+		t.Fatalf("expected error for unknown backend")
+	}
+}
+
+func TestNewEnergyBackend(t *testing.T) {
+	d, err := New(BackendEnergy, Config{})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if _, ok := d.(*EnergyDetector); !ok {
+		t.Fatalf("expected *EnergyDetector, got %T", d)
+	}
+}
+
+func TestDetectSegmentsAppliesPreRoll(t *testing.T) {
+	// A speech run of 5 frames (20ms each below) starting at frame 10,
+	// with pre-roll long enough to reach back 2 frames.
+	const frameSamples = 320 // 20ms at 16kHz
+	isSpeech := func(start, end int) bool {
+		frame := start / frameSamples
+		return frame >= 10 && frame < 15
+	}
+	segments := detectSegments(30*frameSamples, frameSamples, 40 /* 2 frames */, 40, 40 /* 2 frames pre-roll */, isSpeech)
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d: %+v", len(segments), segments)
+	}
+	wantStart := 8 * frameSamples // 10 frames in - 2 frames pre-roll
+	if segments[0].StartSample != wantStart {
+		t.Fatalf("expected pre-rolled start %d, got %d", wantStart, segments[0].StartSample)
+	}
+}
+
+func TestSamplesFromPCM16RoundTrips(t *testing.T) {
+	want := []int16{0, 1, -1, 32767, -32768, 1234}
+	buf := PCM16FromSamples(want)
+	if len(buf) != len(want)*2 {
+		t.Fatalf("expected %d bytes, got %d", len(want)*2, len(buf))
+	}
+	got := SamplesFromPCM16(buf)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d samples, got %d", len(want), len(got))
+	}
+	for i, s := range want {
+		if got[i] != s {
+			t.Fatalf("sample %d: expected %d, got %d", i, s, got[i])
+		}
+	}
+}
+
+func TestSamplesFromPCM16IgnoresTrailingOddByte(t *testing.T) {
+	if got := SamplesFromPCM16([]byte{1, 2, 3}); len(got) != 1 {
+		t.Fatalf("expected 1 complete sample, got %d", len(got))
+	}
+}
+
+func TestDetectSegmentsRequiresMinSpeechMsBeforeOnset(t *testing.T) {
+	const frameSamples = 320
+	// Only 1 frame (20ms) of speech, below a 40ms MinSpeechMs requirement.
+	isSpeech := func(start, end int) bool {
+		frame := start / frameSamples
+		return frame == 5
+	}
+	segments := detectSegments(20*frameSamples, frameSamples, 40, 40, 0, isSpeech)
+	if len(segments) != 0 {
+		t.Fatalf("expected no segments below MinSpeechMs, got %+v", segments)
+	}
+}